@@ -0,0 +1,15 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// CriteriaWeightPolicy menentukan penanganan total bobot kriteria yang tidak sama dengan 1,
+// "reject" (default) menolak perhitungan dan "normalize" menormalisasi ulang seluruh bobot
+func CriteriaWeightPolicy() string {
+	if strings.EqualFold(os.Getenv("CRITERIA_WEIGHT_POLICY"), "normalize") {
+		return "normalize"
+	}
+	return "reject"
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// JobWorkers adalah jumlah job yang dapat berjalan bersamaan, diatur lewat JOB_WORKERS (default 2)
+func JobWorkers() int {
+	workers, err := strconv.Atoi(os.Getenv("JOB_WORKERS"))
+	if err != nil || workers < 1 {
+		return 2
+	}
+	return workers
+}
+
+// InstanceID menandai server yang menjalankan sebuah job, diatur lewat INSTANCE_ID (default hostname-pid).
+// Nilai yang tetap setelah server dimulai ulang membuat job milik server tersebut langsung dipulihkan
+func InstanceID() string {
+	if id := os.Getenv("INSTANCE_ID"); id != "" {
+		return id
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
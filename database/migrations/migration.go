@@ -1,12 +1,19 @@
 package migrations
 
 import (
+	"backend-profitrack/modules/ahp"
+	"backend-profitrack/modules/calculation_run"
 	"backend-profitrack/modules/criteria"
 	"backend-profitrack/modules/criteria_score"
 	"backend-profitrack/modules/final_score"
+	"backend-profitrack/modules/inventory"
+	"backend-profitrack/modules/job"
 	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
 	"backend-profitrack/modules/product"
 	"backend-profitrack/modules/report"
+	"backend-profitrack/modules/report_template"
+	"backend-profitrack/modules/sales"
 	"backend-profitrack/modules/score"
 	"backend-profitrack/modules/user"
 	"fmt"
@@ -19,7 +26,54 @@ func Migrations(db *gorm.DB) {
 	//if err != nil {
 	//	panic(err)
 	//}
-	err = db.AutoMigrate(&user.User{}, &product.Product{}, &criteria.Criteria{}, &method.Method{}, &criteria_score.CriteriaScore{}, &score.Score{}, &final_score.FinalScore{}, &report.Report{}, &report.ReportDetail{})
+	// unique index (period_id, product_id, criteria_id) pada criteria_scores membutuhkan data tanpa duplikat,
+	// hanya nilai terbaru yang dipertahankan. Sebelum ada periode semua nilai berada pada periode 0
+	if db.Migrator().HasTable(&criteria_score.CriteriaScore{}) {
+		duplicate := "a.product_id = b.product_id AND a.criteria_id = b.criteria_id AND a.id < b.id"
+		if db.Migrator().HasColumn(&criteria_score.CriteriaScore{}, "period_id") {
+			duplicate += " AND a.period_id = b.period_id"
+		}
+		err = db.Exec("DELETE FROM criteria_scores a USING criteria_scores b WHERE " + duplicate).Error
+		if err != nil {
+			panic(err)
+		}
+
+		// index lama tanpa period_id akan menolak nilai produk yang sama pada periode berbeda
+		if db.Migrator().HasIndex(&criteria_score.CriteriaScore{}, "idx_criteria_scores_product_criteria") {
+			err = db.Migrator().DropIndex(&criteria_score.CriteriaScore{}, "idx_criteria_scores_product_criteria")
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+	err = db.AutoMigrate(&user.User{}, &product.Product{}, &criteria.Criteria{}, &method.Method{}, &criteria_score.CriteriaScore{}, &score.Score{}, &final_score.FinalScore{}, &report.Report{}, &report.ReportDetail{}, &calculation_run.CalculationRun{}, &ahp.PairwiseComparison{}, &job.Job{}, &sales.Sale{}, &inventory.StockMovement{}, &period.Period{}, &period.ProductFigure{}, &report_template.ReportTemplate{})
+	if err != nil {
+		panic(err)
+	}
+	// kriteria bawaan yang dibuat sebelum adanya rumus diisi dengan rumus yang dulu ditulis di kode,
+	// rumus rasio efisiensi bawaan versi lama yang belum diubah pengguna diganti dengan versi ber-if
+	for name, formula := range criteria.DefaultFormulas {
+		err = db.Model(&criteria.Criteria{}).
+			Where("LOWER(name) = ? AND (formula IS NULL OR formula = '')", name).
+			Updates(map[string]interface{}{"formula": formula, "division_by_zero": criteria.DivisionByZeroAsZero}).Error
+		if err != nil {
+			panic(err)
+		}
+	}
+	err = db.Model(&criteria.Criteria{}).
+		Where("LOWER(name) = ? AND formula IN ?", "rasio efisiensi", []string{
+			"(purchase_cost * sold) / (sold * price_sale)",
+			"(purchase_cost * sold * profit) / (sold * price_sale * profit)",
+		}).
+		Update("formula", criteria.DefaultFormulas["rasio efisiensi"]).Error
+	if err != nil {
+		panic(err)
+	}
+	// laporan lama belum memiliki salinan data produk, diisi dari data produk yang masih ada
+	err = db.Exec(`UPDATE report_details SET product_name = p.name, product_purchase_cost = p.purchase_cost,
+		product_price_sale = p.price_sale, product_profit = p.profit, product_unit = p.unit,
+		product_stock = p.stock, product_sold = p.sold
+		FROM products p WHERE p.id = report_details.product_id AND report_details.product_name IS NULL`).Error
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"io"
+)
+
+// Event adalah satu pesan Server-Sent Events, Name menjadi field "event" dan Data dikirim sebagai JSON
+type Event struct {
+	Name string
+	Data interface{}
+}
+
+// StreamEvents mengirim event dari channel ke klien sampai channel ditutup atau klien terputus
+func StreamEvents(ctx *gin.Context, events <-chan Event) {
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Name, event.Data)
+			return true
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// EventSender mengembalikan fungsi pengirim event yang berhenti menunggu ketika ctx selesai,
+// sehingga goroutine pengirim tidak tertahan setelah klien terputus
+func EventSender(ctx context.Context, events chan<- Event) func(name string, data interface{}) {
+	return func(name string, data interface{}) {
+		select {
+		case events <- Event{Name: name, Data: data}:
+		case <-ctx.Done():
+		}
+	}
+}
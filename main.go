@@ -4,13 +4,25 @@ import (
 	"backend-profitrack/database"
 	"backend-profitrack/database/migrations"
 	"backend-profitrack/middleware"
+	"backend-profitrack/modules/ahp"
+	"backend-profitrack/modules/calculation_run"
+	"backend-profitrack/modules/comparison"
 	"backend-profitrack/modules/criteria"
 	"backend-profitrack/modules/criteria_score"
 	"backend-profitrack/modules/final_score"
+	"backend-profitrack/modules/inventory"
+	"backend-profitrack/modules/job"
 	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/objective_weight"
+	"backend-profitrack/modules/period"
 	"backend-profitrack/modules/product"
 	"backend-profitrack/modules/report"
+	"backend-profitrack/modules/report_template"
+	"backend-profitrack/modules/sales"
 	"backend-profitrack/modules/score"
+	"backend-profitrack/modules/sensitivity"
+	"backend-profitrack/modules/simulation"
+	"backend-profitrack/modules/smaa"
 	"backend-profitrack/modules/user"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -31,12 +43,24 @@ func InitiateRouter(db *gorm.DB) {
 	router.Use(middleware.CORSMiddleware())
 	user.Initiator(router, db)
 	product.Initiator(router, db)
+	sales.Initiator(router, db)
+	inventory.Initiator(router, db)
+	period.Initiator(router, db)
 	criteria.Initiator(router, db)
 	method.Initiator(router, db)
 	criteria_score.Initiator(router, db)
 	score.Initiator(router, db)
 	final_score.Initiator(router, db)
 	report.Initiator(router, db)
+	report_template.Initiator(router, db)
+	calculation_run.Initiator(router, db)
+	ahp.Initiator(router, db)
+	objective_weight.Initiator(router, db)
+	sensitivity.Initiator(router, db)
+	comparison.Initiator(router, db)
+	simulation.Initiator(router, db)
+	smaa.Initiator(router, db)
+	job.Initiator(router, db)
 
 	err := router.Run(":" + os.Getenv("PORT"))
 	if err != nil {
@@ -0,0 +1,113 @@
+package ahp
+
+import (
+	"fmt"
+	"math"
+)
+
+// MaxConsistencyRatio adalah batas rasio konsistensi yang masih dapat diterima
+const MaxConsistencyRatio = 0.1
+
+// randomIndex adalah Random Consistency Index Saaty berdasarkan ukuran matriks
+var randomIndex = []float64{0, 0, 0, 0.58, 0.90, 1.12, 1.24, 1.32, 1.41, 1.45, 1.49, 1.51, 1.48, 1.56, 1.57, 1.59}
+
+// Result adalah vektor prioritas dan ukuran konsistensi sebuah matriks perbandingan
+type Result struct {
+	Weights          []float64
+	LambdaMax        float64
+	ConsistencyIndex float64
+	ConsistencyRatio float64
+}
+
+// ValidateMatrix memeriksa matriks persegi, diagonal bernilai 1, nilai pada skala 1/9 - 9 dan resiprokal
+func ValidateMatrix(matrix [][]float64) error {
+	n := len(matrix)
+	if n < 2 {
+		return fmt.Errorf("matriks perbandingan minimal berukuran 2x2")
+	}
+
+	if n >= len(randomIndex) {
+		return fmt.Errorf("matriks perbandingan maksimal berukuran %dx%d", len(randomIndex)-1, len(randomIndex)-1)
+	}
+
+	for i, row := range matrix {
+		if len(row) != n {
+			return fmt.Errorf("baris %d harus memiliki %d kolom", i+1, n)
+		}
+
+		for j, value := range row {
+			if value < 1.0/9-1e-9 || value > 9+1e-9 {
+				return fmt.Errorf("nilai baris %d kolom %d harus berada pada skala 1/9 - 9", i+1, j+1)
+			}
+
+			if i == j && value != 1 {
+				return fmt.Errorf("nilai diagonal baris %d harus 1", i+1)
+			}
+		}
+	}
+
+	// a_ji = 1 / a_ij, toleransi 1% untuk pembulatan seperti 0.333
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if math.Abs(matrix[i][j]*matrix[j][i]-1) > 0.01 {
+				return fmt.Errorf("nilai baris %d kolom %d harus kebalikan dari baris %d kolom %d", j+1, i+1, i+1, j+1)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Calculate menghitung vektor prioritas dengan metode eigenvector (power iteration),
+// lalu lambda maks, consistency index dan consistency ratio
+func Calculate(matrix [][]float64) Result {
+	n := len(matrix)
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 1 / float64(n)
+	}
+
+	for iteration := 0; iteration < 1000; iteration++ {
+		next := multiply(matrix, weights)
+		total := 0.0
+		for _, value := range next {
+			total += value
+		}
+		for i := range next {
+			next[i] /= total
+		}
+
+		difference := 0.0
+		for i := range next {
+			difference = math.Max(difference, math.Abs(next[i]-weights[i]))
+		}
+		weights = next
+		if difference < 1e-12 {
+			break
+		}
+	}
+
+	// lambda maks = rata-rata (A.w)i / wi
+	lambdaMax := 0.0
+	for i, value := range multiply(matrix, weights) {
+		lambdaMax += value / weights[i]
+	}
+	lambdaMax /= float64(n)
+
+	result := Result{Weights: weights, LambdaMax: lambdaMax}
+	if n > 2 {
+		result.ConsistencyIndex = (lambdaMax - float64(n)) / float64(n-1)
+		result.ConsistencyRatio = result.ConsistencyIndex / randomIndex[n]
+	}
+	return result
+}
+
+func multiply(matrix [][]float64, vector []float64) []float64 {
+	result := make([]float64, len(matrix))
+	for i, row := range matrix {
+		for j, value := range row {
+			result[i] += value * vector[j]
+		}
+	}
+	return result
+}
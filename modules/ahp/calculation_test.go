@@ -0,0 +1,55 @@
+package ahp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name        string
+		matrix      [][]float64
+		wantWeights []float64
+		wantLambda  float64
+		wantCR      float64
+	}{
+		{
+			name:        "2x2 selalu konsisten",
+			matrix:      [][]float64{{1, 3}, {1.0 / 3, 1}},
+			wantWeights: []float64{0.75, 0.25},
+			wantLambda:  2,
+		},
+		{
+			// aij = wi / wj dengan w = (4/7, 2/7, 1/7)
+			name:        "3x3 konsisten",
+			matrix:      [][]float64{{1, 2, 4}, {0.5, 1, 2}, {0.25, 0.5, 1}},
+			wantWeights: []float64{4.0 / 7, 2.0 / 7, 1.0 / 7},
+			wantLambda:  3,
+		},
+		{
+			// setiap baris berjumlah 3.5 sehingga w seragam, CI = (3.5 - 3) / 2, CR = CI / 0.58
+			name:        "3x3 tidak konsisten",
+			matrix:      [][]float64{{1, 2, 0.5}, {0.5, 1, 2}, {2, 0.5, 1}},
+			wantWeights: []float64{1.0 / 3, 1.0 / 3, 1.0 / 3},
+			wantLambda:  3.5,
+			wantCR:      0.25 / 0.58,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Calculate(test.matrix)
+			for i, want := range test.wantWeights {
+				if math.Abs(result.Weights[i]-want) > 1e-9 {
+					t.Errorf("bobot %d = %v, want %v", i, result.Weights[i], want)
+				}
+			}
+			if math.Abs(result.LambdaMax-test.wantLambda) > 1e-9 {
+				t.Errorf("lambda maks = %v, want %v", result.LambdaMax, test.wantLambda)
+			}
+			if math.Abs(result.ConsistencyRatio-test.wantCR) > 1e-9 {
+				t.Errorf("CR = %v, want %v", result.ConsistencyRatio, test.wantCR)
+			}
+		})
+	}
+}
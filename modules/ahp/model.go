@@ -0,0 +1,52 @@
+package ahp
+
+import (
+	"backend-profitrack/modules/criteria"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PairwiseComparison adalah matriks perbandingan berpasangan AHP beserta bobot hasil perhitungannya
+type PairwiseComparison struct {
+	ID               int               `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	UserID           int               `gorm:"integer" json:"user_id"`
+	Matrix           Matrix            `gorm:"type:jsonb;not null" json:"matrix"`
+	Criteria         criteria.Snapshot `gorm:"type:jsonb;not null" json:"criteria"`
+	LambdaMax        float64           `gorm:"double" json:"lambda_max"`
+	ConsistencyIndex float64           `gorm:"double" json:"consistency_index"`
+	ConsistencyRatio float64           `gorm:"double" json:"consistency_ratio"`
+	Applied          bool              `gorm:"default:false" json:"applied"`
+	CreatedAt        time.Time         `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time         `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// Matrix disimpan sebagai kolom jsonb, baris dan kolom mengikuti urutan Criteria
+type Matrix [][]float64
+
+func (m Matrix) Value() (driver.Value, error) {
+	value, err := json.Marshal(m)
+	return string(value), err
+}
+
+func (m *Matrix) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, m)
+	case string:
+		return json.Unmarshal([]byte(data), m)
+	default:
+		return fmt.Errorf("tipe data matriks tidak didukung: %T", value)
+	}
+}
+
+// RequestComparison berisi matriks perbandingan berpasangan, jika CriteriaIDs kosong
+// urutan baris mengikuti urutan ID kriteria
+type RequestComparison struct {
+	CriteriaIDs []int       `json:"criteria_ids"`
+	Matrix      [][]float64 `json:"matrix"`
+}
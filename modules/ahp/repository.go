@@ -0,0 +1,57 @@
+package ahp
+
+import (
+	"backend-profitrack/modules/criteria"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Repository interface {
+	GetAllComparisonRepository() (result []PairwiseComparison, err error)
+	GetComparisonByIdRepository(comparisonID int) (result PairwiseComparison, err error)
+	CreateComparisonRepository(comparison *PairwiseComparison) (err error)
+	ApplyComparisonRepository(comparison *PairwiseComparison) (err error)
+}
+
+type ahpRepository struct {
+	DB *gorm.DB
+}
+
+func NewAHPRepository(db *gorm.DB) Repository {
+	return &ahpRepository{
+		DB: db,
+	}
+}
+
+func (r *ahpRepository) GetAllComparisonRepository() (result []PairwiseComparison, err error) {
+	err = r.DB.Order("id DESC").Find(&result).Error
+	return result, err
+}
+
+func (r *ahpRepository) GetComparisonByIdRepository(comparisonID int) (result PairwiseComparison, err error) {
+	err = r.DB.First(&result, comparisonID).Error
+	return result, err
+}
+
+func (r *ahpRepository) CreateComparisonRepository(comparison *PairwiseComparison) (err error) {
+	err = r.DB.Create(comparison).Error
+	return err
+}
+
+// ApplyComparisonRepository mengganti bobot kriteria dengan vektor prioritas AHP dalam satu transaksi
+func (r *ahpRepository) ApplyComparisonRepository(comparison *PairwiseComparison) (err error) {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for _, item := range comparison.Criteria {
+			err = tx.Model(&criteria.Criteria{}).Where("id = ?", item.ID).
+				Updates(map[string]interface{}{"weight": item.Weight, "updated_at": now}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		comparison.Applied = true
+		comparison.UpdatedAt = now
+		return tx.Model(comparison).Updates(map[string]interface{}{"applied": true, "updated_at": now}).Error
+	})
+}
@@ -0,0 +1,22 @@
+package ahp
+
+import (
+	"backend-profitrack/middleware"
+	"backend-profitrack/modules/criteria"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	repo := NewAHPRepository(db)
+	criteriaRepo := criteria.NewCriteriaRepository(db)
+	service := NewAHPService(repo, criteriaRepo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.GET("/ahp", service.GetAllComparisonService)
+	api.GET("/ahp/:id", service.GetComparisonByIdService)
+	api.POST("/ahp", service.CreateComparisonService)
+	api.POST("/ahp/:id/apply", service.ApplyComparisonService)
+}
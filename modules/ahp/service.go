@@ -0,0 +1,235 @@
+package ahp
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/criteria"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Service interface {
+	GetAllComparisonService(ctx *gin.Context)
+	GetComparisonByIdService(ctx *gin.Context)
+	CreateComparisonService(ctx *gin.Context)
+	ApplyComparisonService(ctx *gin.Context)
+}
+
+type ahpService struct {
+	repository         Repository
+	criteriaRepository criteria.Repository
+}
+
+func NewAHPService(repo Repository, criteriaRepo criteria.Repository) Service {
+	return &ahpService{
+		repository:         repo,
+		criteriaRepository: criteriaRepo,
+	}
+}
+
+func (service *ahpService) GetAllComparisonService(ctx *gin.Context) {
+	comparisons, err := service.repository.GetAllComparisonRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data perbandingan AHP"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(comparisons) == 0 {
+		response := map[string]string{"message": "data perbandingan AHP masih kosong"}
+		helpers.ResponseJSON(ctx, http.StatusOK, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, comparisons)
+}
+
+func (service *ahpService) GetComparisonByIdService(ctx *gin.Context) {
+	comparisonID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	comparison, err := service.repository.GetComparisonByIdRepository(comparisonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Perbandingan AHP dengan ID:%d tidak ditemukan", comparisonID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, comparison)
+}
+
+// CreateComparisonService menghitung bobot dari matriks perbandingan berpasangan,
+// dengan ?apply=true bobot langsung diterapkan ke data kriteria
+func (service *ahpService) CreateComparisonService(ctx *gin.Context) {
+	var request RequestComparison
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	criteriaList, err := service.criteriaRepository.GetAllCriteriaRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data kriteria"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	orderedCriteria, err := orderCriteria(criteriaList, request.CriteriaIDs)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if len(request.Matrix) != len(orderedCriteria) {
+		response := map[string]string{"error": fmt.Sprintf("matriks harus berukuran %dx%d sesuai jumlah kriteria", len(orderedCriteria), len(orderedCriteria))}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if err = ValidateMatrix(request.Matrix); err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	result := Calculate(request.Matrix)
+	if result.ConsistencyRatio > MaxConsistencyRatio {
+		response := map[string]interface{}{
+			"error":             fmt.Sprintf("matriks perbandingan tidak konsisten, CR %.4f melebihi %.1f", result.ConsistencyRatio, MaxConsistencyRatio),
+			"consistency_ratio": result.ConsistencyRatio,
+			"consistency_index": result.ConsistencyIndex,
+			"lambda_max":        result.LambdaMax,
+		}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	snapshot := criteria.NewSnapshot(orderedCriteria)
+	for i := range snapshot {
+		snapshot[i].Weight = result.Weights[i]
+	}
+
+	comparison := PairwiseComparison{
+		UserID:           ctx.GetInt("user_id"),
+		Matrix:           request.Matrix,
+		Criteria:         snapshot,
+		LambdaMax:        result.LambdaMax,
+		ConsistencyIndex: result.ConsistencyIndex,
+		ConsistencyRatio: result.ConsistencyRatio,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+
+	err = service.repository.CreateComparisonRepository(&comparison)
+	if err != nil {
+		response := map[string]string{"error": "gagal menyimpan perbandingan AHP"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	if ctx.Query("apply") == "true" {
+		err = service.repository.ApplyComparisonRepository(&comparison)
+		if err != nil {
+			response := map[string]string{"error": "gagal menerapkan bobot AHP ke data kriteria"}
+			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+			return
+		}
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusCreated, comparison)
+}
+
+func (service *ahpService) ApplyComparisonService(ctx *gin.Context) {
+	comparisonID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	comparison, err := service.repository.GetComparisonByIdRepository(comparisonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Perbandingan AHP dengan ID:%d tidak ditemukan", comparisonID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	criteriaList, err := service.criteriaRepository.GetAllCriteriaRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data kriteria"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	// bobot hanya dapat diterapkan jika kriteria belum ditambah atau dihapus sejak perbandingan dibuat
+	var criteriaIDs []int
+	for _, item := range comparison.Criteria {
+		criteriaIDs = append(criteriaIDs, item.ID)
+	}
+	if _, err = orderCriteria(criteriaList, criteriaIDs); err != nil {
+		response := map[string]string{"error": fmt.Sprintf("data kriteria telah berubah sejak perbandingan dibuat: %v", err)}
+		helpers.ResponseJSON(ctx, http.StatusConflict, response)
+		return
+	}
+
+	err = service.repository.ApplyComparisonRepository(&comparison)
+	if err != nil {
+		response := map[string]string{"error": "gagal menerapkan bobot AHP ke data kriteria"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := map[string]string{"message": "bobot hasil AHP berhasil diterapkan ke data kriteria"}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+// orderCriteria mengurutkan kriteria sesuai criteriaIDs, yang harus mencakup seluruh kriteria tepat satu kali
+func orderCriteria(criteriaList []criteria.Criteria, criteriaIDs []int) ([]criteria.Criteria, error) {
+	if len(criteriaIDs) == 0 {
+		return criteriaList, nil
+	}
+
+	if len(criteriaIDs) != len(criteriaList) {
+		return nil, fmt.Errorf("perbandingan harus mencakup seluruh %d kriteria", len(criteriaList))
+	}
+
+	criteriaMap := make(map[int]criteria.Criteria)
+	for _, item := range criteriaList {
+		criteriaMap[item.ID] = item
+	}
+
+	var result []criteria.Criteria
+	used := make(map[int]bool)
+	for _, criteriaID := range criteriaIDs {
+		item, exists := criteriaMap[criteriaID]
+		if !exists {
+			return nil, fmt.Errorf("kriteria dengan ID:%d tidak ditemukan", criteriaID)
+		}
+		if used[criteriaID] {
+			return nil, fmt.Errorf("kriteria dengan ID:%d disebutkan lebih dari sekali", criteriaID)
+		}
+		used[criteriaID] = true
+		result = append(result, item)
+	}
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package calculation_run
+
+import (
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/method"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type CalculationRun struct {
+	ID           int               `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	MethodID     int               `gorm:"integer;not null" json:"method_id"`
+	PeriodID     int               `gorm:"integer;not null;default:0;index" json:"period_id"`
+	Algorithm    string            `gorm:"varchar(25)" json:"algorithm"`
+	UserID       int               `gorm:"integer" json:"user_id"`
+	Criteria     criteria.Snapshot `gorm:"type:jsonb" json:"criteria"`
+	ProductCount int               `gorm:"integer" json:"product_count"`
+	DurationMs   int64             `gorm:"bigint" json:"duration_ms"`
+	Aggregation  Aggregation       `gorm:"type:jsonb" json:"-"`
+	Method       method.Method     `gorm:"foreignkey:MethodID" json:"-"`
+	CreatedAt    time.Time         `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time         `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+type ResponseCalculationRun struct {
+	ID           int               `json:"id"`
+	MethodID     int               `json:"method_id"`
+	MethodName   string            `json:"method_name"`
+	PeriodID     int               `json:"period_id"`
+	Algorithm    string            `json:"algorithm"`
+	UserID       int               `json:"user_id"`
+	Criteria     criteria.Snapshot `json:"criteria"`
+	ProductCount int               `json:"product_count"`
+	DurationMs   int64             `json:"duration_ms"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+type ResponseRunFinalScore struct {
+	ProductID  int     `json:"product_id"`
+	FinalScore float64 `json:"final_score"`
+	Rank       int     `json:"rank"`
+}
+
+type ResponseCalculationRunDetail struct {
+	ResponseCalculationRun
+	ReportID    *int                    `json:"report_id"`
+	Aggregation Aggregation             `json:"aggregation"`
+	FinalScores []ResponseRunFinalScore `json:"final_scores"`
+}
+
+// Aggregation adalah nilai antara tahap agregasi yang disimpan untuk audit, mis. solusi ideal positif dan
+// negatif setiap kriteria serta jarak D+ dan D- setiap produk pada TOPSIS. Kosong untuk metode konsensus
+type Aggregation struct {
+	Criteria []AggregationCriterion `json:"criteria"`
+	Products []AggregationProduct   `json:"products"`
+}
+
+type AggregationCriterion struct {
+	CriteriaID int                `json:"criteria_id"`
+	Values     map[string]float64 `json:"values"`
+}
+
+type AggregationProduct struct {
+	ProductID int                `json:"product_id"`
+	Values    map[string]float64 `json:"values"`
+}
+
+// Aggregation disimpan sebagai kolom jsonb, nilai kosong disimpan sebagai NULL
+func (a Aggregation) Value() (driver.Value, error) {
+	if len(a.Criteria) == 0 && len(a.Products) == 0 {
+		return nil, nil
+	}
+
+	value, err := json.Marshal(a)
+	return string(value), err
+}
+
+func (a *Aggregation) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*a = Aggregation{}
+		return nil
+	case []byte:
+		return json.Unmarshal(data, a)
+	case string:
+		return json.Unmarshal([]byte(data), a)
+	default:
+		return fmt.Errorf("tipe data agregasi perhitungan tidak didukung: %T", value)
+	}
+}
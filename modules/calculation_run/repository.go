@@ -0,0 +1,60 @@
+package calculation_run
+
+import (
+	"backend-profitrack/modules/final_score"
+	"backend-profitrack/modules/report"
+	"errors"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	GetAllCalculationRunRepository() (result []CalculationRun, err error)
+	GetCalculationRunByIdRepository(runID int) (result CalculationRun, err error)
+	GetFinalScoresByRunIdRepository(runID int) (result []final_score.FinalScore, err error)
+	GetReportByRunIdRepository(runID int) (result *report.Report, err error)
+	GetReportDetailsByReportIdRepository(reportID int) (result []report.ReportDetail, err error)
+}
+
+type calculationRunRepository struct {
+	DB *gorm.DB
+}
+
+func NewCalculationRunRepository(db *gorm.DB) Repository {
+	return &calculationRunRepository{
+		DB: db,
+	}
+}
+
+// GetAllCalculationRunRepository tidak memuat nilai agregasi yang berukuran sebanding jumlah produk
+func (r *calculationRunRepository) GetAllCalculationRunRepository() (result []CalculationRun, err error) {
+	err = r.DB.Preload("Method").Omit("aggregation").Order("id DESC").Find(&result).Error
+	return result, err
+}
+
+func (r *calculationRunRepository) GetCalculationRunByIdRepository(runID int) (result CalculationRun, err error) {
+	err = r.DB.Preload("Method").First(&result, runID).Error
+	return result, err
+}
+
+func (r *calculationRunRepository) GetFinalScoresByRunIdRepository(runID int) (result []final_score.FinalScore, err error) {
+	err = r.DB.Where("calculation_run_id = ?", runID).Order("final_score DESC").Find(&result).Error
+	return result, err
+}
+
+// GetReportByRunIdRepository mengembalikan nil jika hasil perhitungan belum dijadikan laporan
+func (r *calculationRunRepository) GetReportByRunIdRepository(runID int) (result *report.Report, err error) {
+	var getReport report.Report
+	err = r.DB.Where("calculation_run_id = ?", runID).Order("id DESC").First(&getReport).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &getReport, nil
+}
+
+func (r *calculationRunRepository) GetReportDetailsByReportIdRepository(reportID int) (result []report.ReportDetail, err error) {
+	err = r.DB.Where("report_id = ?", reportID).Order("final_score DESC").Find(&result).Error
+	return result, err
+}
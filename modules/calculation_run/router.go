@@ -0,0 +1,18 @@
+package calculation_run
+
+import (
+	"backend-profitrack/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	repo := NewCalculationRunRepository(db)
+	service := NewCalculationRunService(repo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.GET("/runs", service.GetAllCalculationRunService)
+	api.GET("/runs/:id", service.GetCalculationRunByIdService)
+}
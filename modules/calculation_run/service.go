@@ -0,0 +1,133 @@
+package calculation_run
+
+import (
+	"backend-profitrack/helpers"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+)
+
+type Service interface {
+	GetAllCalculationRunService(ctx *gin.Context)
+	GetCalculationRunByIdService(ctx *gin.Context)
+}
+
+type calculationRunService struct {
+	repository Repository
+}
+
+func NewCalculationRunService(repo Repository) Service {
+	return &calculationRunService{
+		repository: repo,
+	}
+}
+
+func (service *calculationRunService) GetAllCalculationRunService(ctx *gin.Context) {
+	runs, err := service.repository.GetAllCalculationRunRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data riwayat perhitungan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	var result []ResponseCalculationRun
+	for _, run := range runs {
+		result = append(result, toResponse(run))
+	}
+
+	if result == nil {
+		response := map[string]string{"message": "data riwayat perhitungan masih kosong"}
+		helpers.ResponseJSON(ctx, http.StatusOK, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+func (service *calculationRunService) GetCalculationRunByIdService(ctx *gin.Context) {
+	runID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	run, err := service.repository.GetCalculationRunByIdRepository(runID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Riwayat perhitungan dengan ID:%d tidak ditemukan", runID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	result := ResponseCalculationRunDetail{
+		ResponseCalculationRun: toResponse(run),
+		Aggregation:            run.Aggregation,
+		FinalScores:            []ResponseRunFinalScore{},
+	}
+
+	// nilai akhir dipindahkan ke detail laporan saat laporan dibuat
+	getReport, err := service.repository.GetReportByRunIdRepository(runID)
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil laporan dari riwayat perhitungan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	if getReport != nil {
+		result.ReportID = &getReport.ID
+		details, err := service.repository.GetReportDetailsByReportIdRepository(getReport.ID)
+		if err != nil {
+			response := map[string]string{"error": "gagal mengambil detail laporan"}
+			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+			return
+		}
+
+		for i, detail := range details {
+			result.FinalScores = append(result.FinalScores, ResponseRunFinalScore{
+				ProductID:  detail.ProductID,
+				FinalScore: detail.FinalScore,
+				Rank:       i + 1,
+			})
+		}
+	} else {
+		finalScores, err := service.repository.GetFinalScoresByRunIdRepository(runID)
+		if err != nil {
+			response := map[string]string{"error": "gagal mengambil data nilai akhir"}
+			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+			return
+		}
+
+		for i, score := range finalScores {
+			result.FinalScores = append(result.FinalScores, ResponseRunFinalScore{
+				ProductID:  score.ProductID,
+				FinalScore: score.FinalScore,
+				Rank:       i + 1,
+			})
+		}
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+func toResponse(run CalculationRun) ResponseCalculationRun {
+	return ResponseCalculationRun{
+		ID:           run.ID,
+		MethodID:     run.MethodID,
+		MethodName:   run.Method.Name,
+		PeriodID:     run.PeriodID,
+		Algorithm:    run.Algorithm,
+		UserID:       run.UserID,
+		Criteria:     run.Criteria,
+		ProductCount: run.ProductCount,
+		DurationMs:   run.DurationMs,
+		CreatedAt:    run.CreatedAt,
+	}
+}
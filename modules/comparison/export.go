@@ -0,0 +1,168 @@
+package comparison
+
+import (
+	"backend-profitrack/helpers"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/xuri/excelize/v2"
+	"net/http"
+	"time"
+)
+
+func comparisonHeaders(result ResponseComparison) []string {
+	headers := []string{"No", "Nama Produk"}
+	for _, source := range result.Sources {
+		headers = append(headers, fmt.Sprintf("Peringkat %s", source.Name))
+	}
+	for _, source := range result.Sources[1:] {
+		headers = append(headers, fmt.Sprintf("Selisih %s", source.Name))
+	}
+	return headers
+}
+
+func comparisonRow(index int, product ResponseProductRank) []interface{} {
+	row := []interface{}{index + 1, product.ProductName}
+	for _, rank := range product.Ranks {
+		row = append(row, rank)
+	}
+	for _, delta := range product.RankDeltas[1:] {
+		row = append(row, delta)
+	}
+	return row
+}
+
+func (service *comparisonService) exportExcel(ctx *gin.Context, result ResponseComparison) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	style, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold: true,
+		},
+	})
+	if err != nil {
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membuat style"})
+		return
+	}
+
+	// Sheet peringkat per produk
+	sheet := "Perbandingan"
+	f.SetSheetName("Sheet1", sheet)
+	for i, header := range comparisonHeaders(result) {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, header)
+		f.SetCellStyle(sheet, cell, cell, style)
+	}
+	for i, product := range result.Products {
+		for j, value := range comparisonRow(i, product) {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+2)
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+
+	// Sheet korelasi antar sumber
+	correlationSheet := "Korelasi"
+	f.NewSheet(correlationSheet)
+	for i, header := range []string{"Sumber A", "Sumber B", "Spearman Rho", "Kendall Tau"} {
+		cell := string(rune('A'+i)) + "1"
+		f.SetCellValue(correlationSheet, cell, header)
+		f.SetCellStyle(correlationSheet, cell, cell, style)
+	}
+	for i, correlation := range result.Correlations {
+		row := i + 2
+		f.SetCellValue(correlationSheet, fmt.Sprintf("A%d", row), result.Sources[correlation.SourceA].Name)
+		f.SetCellValue(correlationSheet, fmt.Sprintf("B%d", row), result.Sources[correlation.SourceB].Name)
+		f.SetCellValue(correlationSheet, fmt.Sprintf("C%d", row), correlation.Spearman)
+		f.SetCellValue(correlationSheet, fmt.Sprintf("D%d", row), correlation.Kendall)
+	}
+
+	fileName := fmt.Sprintf("perbandingan-peringkat-%s.xlsx", time.Now().Format("02-01-2006"))
+
+	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+
+	if err := f.Write(ctx.Writer); err != nil {
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membuat file Excel"})
+		return
+	}
+}
+
+func (service *comparisonService) exportPDF(ctx *gin.Context, result ResponseComparison) {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.AddPage()
+
+	pdf.SetFont("Times", "B", 16)
+	pdf.CellFormat(0, 10, "Laporan Perbandingan Peringkat", "", 1, "C", false, 0, "")
+	pdf.Ln(5)
+
+	// Tabel korelasi
+	pdf.SetFont("Times", "B", 10)
+	pdf.SetFillColor(200, 200, 200)
+	correlationWidths := []float64{80, 80, 50, 50}
+	for i, header := range []string{"Sumber A", "Sumber B", "Spearman Rho", "Kendall Tau"} {
+		pdf.CellFormat(correlationWidths[i], 10, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Times", "", 10)
+	for _, correlation := range result.Correlations {
+		pdf.CellFormat(correlationWidths[0], 8, result.Sources[correlation.SourceA].Name, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(correlationWidths[1], 8, result.Sources[correlation.SourceB].Name, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(correlationWidths[2], 8, fmt.Sprintf("%.4f", correlation.Spearman), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(correlationWidths[3], 8, fmt.Sprintf("%.4f", correlation.Kendall), "1", 0, "C", false, 0, "")
+		pdf.Ln(-1)
+	}
+	pdf.Ln(8)
+
+	// Tabel peringkat, kolom selain No dan Nama Produk dibagi rata
+	headers := comparisonHeaders(result)
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	available := pageWidth - left - right - 10 - 70
+	colWidths := []float64{10, 70}
+	for range headers[2:] {
+		colWidths = append(colWidths, available/float64(len(headers)-2))
+	}
+
+	pdf.SetFont("Times", "B", 10)
+	pdf.SetFillColor(200, 200, 200)
+	for i, header := range headers {
+		pdf.CellFormat(colWidths[i], 10, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Times", "", 10)
+	for i, product := range result.Products {
+		fill := i%2 == 1
+		if fill {
+			pdf.SetFillColor(240, 240, 240)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+
+		for j, value := range comparisonRow(i, product) {
+			align := "C"
+			if j == 1 {
+				align = "L"
+			}
+			pdf.CellFormat(colWidths[j], 8, fmt.Sprint(value), "1", 0, align, fill, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	pdf.Ln(10)
+	pdf.SetFont("Times", "I", 8)
+	pdf.CellFormat(0, 10, fmt.Sprintf("Laporan dibuat pada: %s", time.Now().Format("02-01-2006 15:04:05")), "", 0, "R", false, 0, "")
+
+	fileName := fmt.Sprintf("perbandingan-peringkat-%s.pdf", time.Now().Format("02-01-2006"))
+
+	ctx.Header("Content-Type", "application/pdf")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+
+	if err := pdf.Output(ctx.Writer); err != nil {
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membuat file PDF"})
+		return
+	}
+}
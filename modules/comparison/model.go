@@ -0,0 +1,45 @@
+package comparison
+
+// Jenis sumber peringkat yang dapat dibandingkan
+const (
+	SourceMethod = "method"
+	SourceReport = "report"
+)
+
+type ResponseSource struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ResponseProductRank berisi skor dan peringkat produk pada setiap sumber sesuai urutan Sources,
+// RankDeltas adalah selisih peringkat terhadap sumber pertama
+type ResponseProductRank struct {
+	ProductID   int       `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Scores      []float64 `json:"scores"`
+	Ranks       []int     `json:"ranks"`
+	RankDeltas  []int     `json:"rank_deltas"`
+}
+
+// ResponseCorrelation berisi korelasi peringkat antara Sources[SourceA] dan Sources[SourceB]
+type ResponseCorrelation struct {
+	SourceA  int     `json:"source_a"`
+	SourceB  int     `json:"source_b"`
+	Spearman float64 `json:"spearman_rho"`
+	Kendall  float64 `json:"kendall_tau"`
+}
+
+type ResponseComparison struct {
+	Sources          []ResponseSource      `json:"sources"`
+	Products         []ResponseProductRank `json:"products"`
+	Correlations     []ResponseCorrelation `json:"correlations"`
+	ExcludedProducts []int                 `json:"excluded_products"`
+}
+
+// rankingSource adalah skor akhir per produk dari satu metode atau satu laporan
+type rankingSource struct {
+	source ResponseSource
+	scores map[int]float64
+	names  map[int]string
+}
@@ -0,0 +1,24 @@
+package comparison
+
+import (
+	"backend-profitrack/middleware"
+	"backend-profitrack/modules/final_score"
+	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/product"
+	"backend-profitrack/modules/report"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	finalScoreRepo := final_score.NewFinalScoreRepository(db)
+	reportRepo := report.NewReportRepository(db)
+	methodRepo := method.NewMethodRepository(db)
+	productRepo := product.NewProductRepository(db)
+	service := NewComparisonService(finalScoreRepo, reportRepo, methodRepo, productRepo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.GET("/comparisons", service.CompareService)
+}
@@ -0,0 +1,299 @@
+package comparison
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/final_score"
+	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/product"
+	"backend-profitrack/modules/ranking"
+	"backend-profitrack/modules/report"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Service interface {
+	CompareService(ctx *gin.Context)
+}
+
+type comparisonService struct {
+	finalScoreRepository final_score.Repository
+	reportRepository     report.Repository
+	methodRepository     method.Repository
+	productRepository    product.Repository
+}
+
+func NewComparisonService(finalScoreRepo final_score.Repository, reportRepo report.Repository, methodRepo method.Repository, productRepo product.Repository) Service {
+	return &comparisonService{
+		finalScoreRepository: finalScoreRepo,
+		reportRepository:     reportRepo,
+		methodRepository:     methodRepo,
+		productRepository:    productRepo,
+	}
+}
+
+// CompareService membandingkan peringkat antar metode (?methods=1,2) atau antar laporan (?reports=3,4),
+// query format=xlsx|pdf mengunduh hasil perbandingan
+func (service *comparisonService) CompareService(ctx *gin.Context) {
+	methodsQuery, reportsQuery := ctx.Query("methods"), ctx.Query("reports")
+	if (methodsQuery == "") == (reportsQuery == "") {
+		response := map[string]string{"error": "gunakan salah satu query methods atau reports"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	format := strings.ToLower(ctx.Query("format"))
+	if format != "" && format != "xlsx" && format != "pdf" {
+		response := map[string]string{"error": "format harus xlsx atau pdf"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	var sources []rankingSource
+	var statusCode int
+	var err error
+	if methodsQuery != "" {
+		sources, statusCode, err = service.methodSources(methodsQuery)
+	} else {
+		sources, statusCode, err = service.reportSources(reportsQuery)
+	}
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	productNames, err := service.productNames(sources)
+	if err != nil {
+		response := map[string]string{"error": "gagal mendapatkan data produk"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	result := compare(sources, productNames)
+	if len(result.Products) < 2 {
+		response := map[string]string{"error": "produk yang sama pada setiap sumber kurang dari 2, perbandingan tidak dapat dihitung"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	switch format {
+	case "xlsx":
+		service.exportExcel(ctx, result)
+	case "pdf":
+		service.exportPDF(ctx, result)
+	default:
+		helpers.ResponseJSON(ctx, http.StatusOK, result)
+	}
+}
+
+func parseIDs(query string) ([]int, error) {
+	var ids []int
+	seen := map[int]bool{}
+	for _, part := range strings.Split(query, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, errors.New("ID tidak sesuai")
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("ID:%d disebutkan lebih dari sekali", id)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) < 2 {
+		return nil, errors.New("minimal 2 ID untuk dibandingkan")
+	}
+	return ids, nil
+}
+
+func (service *comparisonService) methodSources(query string) ([]rankingSource, int, error) {
+	ids, err := parseIDs(query)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	var sources []rankingSource
+	for _, id := range ids {
+		getMethod, err := service.methodRepository.GetMethodByIdRepository(id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, http.StatusNotFound, fmt.Errorf("Metode dengan ID:%d tidak ditemukan", id)
+			}
+			return nil, http.StatusInternalServerError, err
+		}
+
+		finalScores, err := service.finalScoreRepository.GetAllFinalScoreByMethodIDRepository(id)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if len(finalScores) == 0 {
+			return nil, http.StatusBadRequest, fmt.Errorf("metode %s belum memiliki hasil perhitungan", getMethod.Name)
+		}
+
+		scores := make(map[int]float64, len(finalScores))
+		for _, finalScore := range finalScores {
+			scores[finalScore.ProductID] = finalScore.FinalScore
+		}
+
+		sources = append(sources, rankingSource{
+			source: ResponseSource{Type: SourceMethod, ID: getMethod.ID, Name: getMethod.Name},
+			scores: scores,
+		})
+	}
+	return sources, http.StatusOK, nil
+}
+
+func (service *comparisonService) reportSources(query string) ([]rankingSource, int, error) {
+	ids, err := parseIDs(query)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	var sources []rankingSource
+	for _, id := range ids {
+		getReport, err := service.reportRepository.GetReportByIDRepository(id)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if getReport.ID == 0 {
+			return nil, http.StatusNotFound, fmt.Errorf("Laporan dengan ID:%d tidak ditemukan", id)
+		}
+
+		details, err := service.reportRepository.GetAllReportDetailRepository(id)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+
+		scores := make(map[int]float64, len(details))
+		names := make(map[int]string, len(details))
+		for _, detail := range details {
+			scores[detail.ProductID] = detail.FinalScore
+			names[detail.ProductID] = detail.Product.Name
+		}
+
+		sources = append(sources, rankingSource{
+			source: ResponseSource{Type: SourceReport, ID: getReport.ID, Name: getReport.ReportCode},
+			scores: scores,
+			names:  names,
+		})
+	}
+	return sources, http.StatusOK, nil
+}
+
+// productNames mengutamakan nama produk dari snapshot laporan dan melengkapinya dari data produk
+func (service *comparisonService) productNames(sources []rankingSource) (map[int]string, error) {
+	names := map[int]string{}
+	for _, source := range sources {
+		for id, name := range source.names {
+			if _, ok := names[id]; !ok && name != "" {
+				names[id] = name
+			}
+		}
+	}
+
+	for _, source := range sources {
+		if source.source.Type != SourceMethod {
+			continue
+		}
+
+		products, err := service.productRepository.GetAllProductRepository()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range products {
+			if _, ok := names[item.ID]; !ok {
+				names[item.ID] = item.Name
+			}
+		}
+		break
+	}
+	return names, nil
+}
+
+// compare hanya menyejajarkan produk yang ada di semua sumber, peringkat dihitung ulang di antara produk tersebut
+func compare(sources []rankingSource, productNames map[int]string) ResponseComparison {
+	result := ResponseComparison{
+		Sources:          make([]ResponseSource, len(sources)),
+		Products:         []ResponseProductRank{},
+		Correlations:     []ResponseCorrelation{},
+		ExcludedProducts: []int{},
+	}
+	for i, source := range sources {
+		result.Sources[i] = source.source
+	}
+
+	var common []int
+	excluded := map[int]bool{}
+	for _, source := range sources {
+		for productID := range source.scores {
+			inAll := true
+			for _, other := range sources {
+				if _, ok := other.scores[productID]; !ok {
+					inAll = false
+					break
+				}
+			}
+			if !inAll {
+				excluded[productID] = true
+			}
+		}
+	}
+	for productID := range sources[0].scores {
+		if !excluded[productID] {
+			common = append(common, productID)
+		}
+	}
+	sort.Ints(common)
+	for productID := range excluded {
+		result.ExcludedProducts = append(result.ExcludedProducts, productID)
+	}
+	sort.Ints(result.ExcludedProducts)
+
+	scores := make([][]float64, len(sources))
+	ranks := make([][]int, len(sources))
+	for i, source := range sources {
+		scores[i] = make([]float64, len(common))
+		for j, productID := range common {
+			scores[i][j] = source.scores[productID]
+		}
+		ranks[i] = ranking.Ranks(scores[i])
+	}
+
+	for j, productID := range common {
+		row := ResponseProductRank{
+			ProductID:   productID,
+			ProductName: productNames[productID],
+			Scores:      make([]float64, len(sources)),
+			Ranks:       make([]int, len(sources)),
+			RankDeltas:  make([]int, len(sources)),
+		}
+		for i := range sources {
+			row.Scores[i] = scores[i][j]
+			row.Ranks[i] = ranks[i][j]
+			row.RankDeltas[i] = ranks[i][j] - ranks[0][j]
+		}
+		result.Products = append(result.Products, row)
+	}
+	sort.SliceStable(result.Products, func(a, b int) bool {
+		return result.Products[a].Ranks[0] < result.Products[b].Ranks[0]
+	})
+
+	for a := 0; a < len(sources); a++ {
+		for b := a + 1; b < len(sources); b++ {
+			result.Correlations = append(result.Correlations, ResponseCorrelation{
+				SourceA:  a,
+				SourceB:  b,
+				Spearman: Spearman(scores[a], scores[b]),
+				Kendall:  Kendall(scores[a], scores[b]),
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package comparison
+
+import (
+	"backend-profitrack/modules/ranking"
+	"math"
+)
+
+// Spearman menghitung rho sebagai korelasi Pearson dari peringkat rata-rata sehingga skor kembar tetap ditangani
+func Spearman(a []float64, b []float64) float64 {
+	rankA, rankB := ranking.AverageRanks(a), ranking.AverageRanks(b)
+	n := float64(len(a))
+	if n < 2 {
+		return 0
+	}
+
+	var meanA, meanB float64
+	for i := range rankA {
+		meanA += rankA[i] / n
+		meanB += rankB[i] / n
+	}
+
+	var covariance, varianceA, varianceB float64
+	for i := range rankA {
+		covariance += (rankA[i] - meanA) * (rankB[i] - meanB)
+		varianceA += math.Pow(rankA[i]-meanA, 2)
+		varianceB += math.Pow(rankB[i]-meanB, 2)
+	}
+
+	if varianceA == 0 || varianceB == 0 {
+		return 0
+	}
+	return covariance / math.Sqrt(varianceA*varianceB)
+}
+
+// Kendall menghitung tau-b: (concordant - discordant) / sqrt((n0 - tiesA) * (n0 - tiesB))
+func Kendall(a []float64, b []float64) float64 {
+	var concordant, discordant, tiesA, tiesB float64
+	for i := 0; i < len(a); i++ {
+		for j := i + 1; j < len(a); j++ {
+			directionA := sign(a[i] - a[j])
+			directionB := sign(b[i] - b[j])
+			switch {
+			case directionA == 0 && directionB == 0:
+				tiesA++
+				tiesB++
+			case directionA == 0:
+				tiesA++
+			case directionB == 0:
+				tiesB++
+			case directionA == directionB:
+				concordant++
+			default:
+				discordant++
+			}
+		}
+	}
+
+	n0 := float64(len(a)*(len(a)-1)) / 2
+	denominator := math.Sqrt((n0 - tiesA) * (n0 - tiesB))
+	if denominator == 0 {
+		return 0
+	}
+	return (concordant - discordant) / denominator
+}
+
+func sign(value float64) int {
+	switch {
+	case value > 0:
+		return 1
+	case value < 0:
+		return -1
+	default:
+		return 0
+	}
+}
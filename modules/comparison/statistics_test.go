@@ -0,0 +1,35 @@
+package comparison
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRankCorrelation(t *testing.T) {
+	tests := []struct {
+		name         string
+		a, b         []float64
+		wantSpearman float64
+		wantKendall  float64
+	}{
+		{"urutan sama", []float64{1, 2, 3}, []float64{10, 20, 30}, 1, 1},
+		{"urutan terbalik", []float64{1, 2, 3}, []float64{3, 2, 1}, -1, -1},
+		// satu pasangan bertukar: rho = 1 - 6*2/(4*15), tau = (5-1)/6
+		{"satu pasangan bertukar", []float64{1, 2, 3, 4}, []float64{1, 3, 2, 4}, 0.8, 2.0 / 3},
+		// peringkat a (2.5, 2.5, 1), b (3, 2, 1): rho = 1.5/sqrt(1.5*2), tau-b = 2/sqrt(2*3)
+		{"skor kembar", []float64{1, 1, 2}, []float64{1, 2, 3}, 1.5 / math.Sqrt(3), 2 / math.Sqrt(6)},
+		{"skor konstan", []float64{1, 1, 1}, []float64{1, 2, 3}, 0, 0},
+		{"satu produk", []float64{1}, []float64{1}, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Spearman(test.a, test.b); math.Abs(got-test.wantSpearman) > 1e-9 {
+				t.Errorf("Spearman = %v, want %v", got, test.wantSpearman)
+			}
+			if got := Kendall(test.a, test.b); math.Abs(got-test.wantKendall) > 1e-9 {
+				t.Errorf("Kendall = %v, want %v", got, test.wantKendall)
+			}
+		})
+	}
+}
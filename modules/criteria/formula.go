@@ -0,0 +1,323 @@
+package criteria
+
+import (
+	"backend-profitrack/modules/product"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrDivisionByZero dikembalikan saat rumus membagi dengan nol untuk sebuah produk
+var ErrDivisionByZero = errors.New("pembagian dengan nol")
+
+// Formula adalah rumus aritmatika (+, -, *, /, kurung) atas variabel pada product.FormulaVariables.
+// Perbandingan (<, <=, >, >=, ==, !=) bernilai 1 atau 0 dan if(kondisi, nilai, lainnya) hanya menghitung
+// cabang yang dipilih, sehingga pembagian dengan nol dapat dicegah langsung di rumus
+type Formula struct {
+	root formulaNode
+}
+
+type formulaNode interface {
+	evaluate(variables map[string]float64) (float64, error)
+}
+
+type numberNode float64
+
+type variableNode string
+
+type negateNode struct {
+	operand formulaNode
+}
+
+type binaryNode struct {
+	operator    byte
+	left, right formulaNode
+}
+
+type compareNode struct {
+	operator    string
+	left, right formulaNode
+}
+
+type conditionalNode struct {
+	condition, then, otherwise formulaNode
+}
+
+func (n numberNode) evaluate(map[string]float64) (float64, error) {
+	return float64(n), nil
+}
+
+func (n variableNode) evaluate(variables map[string]float64) (float64, error) {
+	value, exists := variables[string(n)]
+	if !exists {
+		return 0, fmt.Errorf("variabel %s tidak tersedia", string(n))
+	}
+	return value, nil
+}
+
+func (n negateNode) evaluate(variables map[string]float64) (float64, error) {
+	value, err := n.operand.evaluate(variables)
+	return -value, err
+}
+
+func (n binaryNode) evaluate(variables map[string]float64) (float64, error) {
+	left, err := n.left.evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	right, err := n.right.evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.operator {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	default:
+		if right == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return left / right, nil
+	}
+}
+
+func (n compareNode) evaluate(variables map[string]float64) (float64, error) {
+	left, err := n.left.evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	right, err := n.right.evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	var result bool
+	switch n.operator {
+	case "<":
+		result = left < right
+	case "<=":
+		result = left <= right
+	case ">":
+		result = left > right
+	case ">=":
+		result = left >= right
+	case "==":
+		result = left == right
+	default:
+		result = left != right
+	}
+
+	if result {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (n conditionalNode) evaluate(variables map[string]float64) (float64, error) {
+	condition, err := n.condition.evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	if condition != 0 {
+		return n.then.evaluate(variables)
+	}
+	return n.otherwise.evaluate(variables)
+}
+
+// ParseFormula memeriksa rumus dan hanya menerima variabel yang terdaftar pada product.FormulaVariables
+func ParseFormula(expression string) (*Formula, error) {
+	parser := &formulaParser{input: expression}
+	if strings.TrimSpace(expression) == "" {
+		return nil, fmt.Errorf("rumus tidak boleh kosong")
+	}
+
+	root, err := parser.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	parser.skipSpaces()
+	if parser.position < len(parser.input) {
+		return nil, fmt.Errorf("karakter tidak terduga '%c' pada posisi %d", parser.input[parser.position], parser.position+1)
+	}
+
+	return &Formula{root: root}, nil
+}
+
+func (f *Formula) Evaluate(variables map[string]float64) (float64, error) {
+	return f.root.evaluate(variables)
+}
+
+// formulaParser adalah recursive descent parser:
+// expression = sum (('<' | '<=' | '>' | '>=' | '==' | '!=') sum)?
+// sum        = term (('+' | '-') term)*
+// term       = factor (('*' | '/') factor)*
+// factor     = ('+' | '-') factor | number | variable | 'if' '(' expression ',' expression ',' expression ')' | '(' expression ')'
+type formulaParser struct {
+	input    string
+	position int
+}
+
+func (p *formulaParser) skipSpaces() {
+	for p.position < len(p.input) && p.input[p.position] == ' ' {
+		p.position++
+	}
+}
+
+func (p *formulaParser) peek() byte {
+	p.skipSpaces()
+	if p.position >= len(p.input) {
+		return 0
+	}
+	return p.input[p.position]
+}
+
+// comparisonOperator membaca operator perbandingan pada posisi saat ini tanpa memajukan parser
+func (p *formulaParser) comparisonOperator() string {
+	p.skipSpaces()
+	for _, operator := range []string{"<=", ">=", "==", "!=", "<", ">"} {
+		if strings.HasPrefix(p.input[p.position:], operator) {
+			return operator
+		}
+	}
+	return ""
+}
+
+func (p *formulaParser) parseExpression() (formulaNode, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	operator := p.comparisonOperator()
+	if operator == "" {
+		return left, nil
+	}
+	p.position += len(operator)
+
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	return compareNode{operator: operator, left: left, right: right}, nil
+}
+
+func (p *formulaParser) parseSum() (formulaNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for operator := p.peek(); operator == '+' || operator == '-'; operator = p.peek() {
+		p.position++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *formulaParser) parseTerm() (formulaNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	for operator := p.peek(); operator == '*' || operator == '/'; operator = p.peek() {
+		p.position++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *formulaParser) parseFactor() (formulaNode, error) {
+	current := p.peek()
+	switch {
+	case current == 0:
+		return nil, fmt.Errorf("rumus tidak lengkap")
+	case current == '+':
+		p.position++
+		return p.parseFactor()
+	case current == '-':
+		p.position++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{operand: operand}, nil
+	case current == '(':
+		p.position++
+		node, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("kurung tutup tidak ditemukan pada posisi %d", p.position+1)
+		}
+		p.position++
+		return node, nil
+	case current == '.' || unicode.IsDigit(rune(current)):
+		start := p.position
+		for p.position < len(p.input) && (p.input[p.position] == '.' || unicode.IsDigit(rune(p.input[p.position]))) {
+			p.position++
+		}
+		value, err := strconv.ParseFloat(p.input[start:p.position], 64)
+		if err != nil {
+			return nil, fmt.Errorf("angka tidak valid '%s'", p.input[start:p.position])
+		}
+		return numberNode(value), nil
+	case current == '_' || unicode.IsLetter(rune(current)):
+		start := p.position
+		for p.position < len(p.input) && (p.input[p.position] == '_' || unicode.IsLetter(rune(p.input[p.position])) || unicode.IsDigit(rune(p.input[p.position]))) {
+			p.position++
+		}
+		name := strings.ToLower(p.input[start:p.position])
+		if name == "if" && p.peek() == '(' {
+			p.position++
+			return p.parseConditional()
+		}
+		if _, exists := product.FormulaVariables[name]; !exists {
+			return nil, fmt.Errorf("variabel '%s' tidak dikenali", name)
+		}
+		return variableNode(name), nil
+	default:
+		return nil, fmt.Errorf("karakter tidak terduga '%c' pada posisi %d", current, p.position+1)
+	}
+}
+
+// parseConditional membaca argumen if(kondisi, nilai, lainnya) setelah kurung buka
+func (p *formulaParser) parseConditional() (formulaNode, error) {
+	arguments := make([]formulaNode, 3)
+	for i := range arguments {
+		node, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		arguments[i] = node
+
+		expected := byte(',')
+		if i == len(arguments)-1 {
+			expected = ')'
+		}
+		if p.peek() != expected {
+			return nil, fmt.Errorf("if membutuhkan 3 argumen, '%c' tidak ditemukan pada posisi %d", expected, p.position+1)
+		}
+		p.position++
+	}
+	return conditionalNode{condition: arguments[0], then: arguments[1], otherwise: arguments[2]}, nil
+}
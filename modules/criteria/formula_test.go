@@ -0,0 +1,219 @@
+package criteria
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseFormulaEvaluate(t *testing.T) {
+	variables := map[string]float64{
+		"purchase_cost": 8000,
+		"price_sale":    10000,
+		"profit":        2000,
+		"stock":         50,
+		"sold":          20,
+		"revenue":       200000,
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       float64
+	}{
+		{"perkalian sebelum penjumlahan", "1 + 2 * 3", 7},
+		{"pembagian sebelum pengurangan", "10 - 6 / 2", 7},
+		{"operator sama dari kiri ke kanan", "20 / 4 / 5", 1},
+		{"pengurangan dari kiri ke kanan", "10 - 4 - 3", 3},
+		{"kurung mengubah urutan", "(1 + 2) * 3", 9},
+		{"kurung bersarang", "((2 + 3) * (4 - 1)) / 5", 3},
+		{"minus unary", "-3 + 5", 2},
+		{"minus unary pada kurung", "-(2 + 3) * 2", -10},
+		{"minus unary ganda", "--4", 4},
+		{"plus unary", "+4 * 2", 8},
+		{"minus unary setelah operator", "6 * -2", -12},
+		{"angka desimal", "0.5 * 4", 2},
+		{"variabel", "(profit * sold) / (purchase_cost * stock)", 0.1},
+		{"nama variabel tidak peka huruf besar", "PROFIT / Price_Sale", 0.2},
+		{"spasi diabaikan", "  profit   +  1 ", 2001},
+		{"perbandingan benar", "profit > 1000", 1},
+		{"perbandingan salah", "profit <= 1000", 0},
+		{"perbandingan setelah aritmatika", "profit * 2 == 4000", 1},
+		{"if cabang benar", "if(sold != 0, profit, 0)", 2000},
+		{"if cabang salah", "if(stock < sold, 1, 2)", 2},
+		{"if bersarang", "if(profit == 0, 0, if(sold > 10, 3, 4)) + 1", 4},
+		{"cabang if yang tidak dipilih tidak dihitung", "if(profit == 2000, 5, profit / 0)", 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			formula, err := ParseFormula(test.expression)
+			if err != nil {
+				t.Fatalf("ParseFormula(%q) error: %v", test.expression, err)
+			}
+
+			got, err := formula.Evaluate(variables)
+			if err != nil {
+				t.Fatalf("Evaluate(%q) error: %v", test.expression, err)
+			}
+			if math.Abs(got-test.want) > 1e-9 {
+				t.Errorf("Evaluate(%q) = %v, want %v", test.expression, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseFormulaErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		message    string
+	}{
+		{"kosong", "", "tidak boleh kosong"},
+		{"hanya spasi", "   ", "tidak boleh kosong"},
+		{"variabel tidak dikenal", "profit / margin", "variabel 'margin' tidak dikenali"},
+		{"token sisa", "profit sold", "karakter tidak terduga 's' pada posisi 8"},
+		{"kurung tutup berlebih", "(profit))", "karakter tidak terduga ')'"},
+		{"kurung tidak ditutup", "(profit + 1", "kurung tutup tidak ditemukan"},
+		{"operator di akhir", "profit +", "rumus tidak lengkap"},
+		{"operator ganda", "profit * / sold", "karakter tidak terduga '/'"},
+		{"karakter tidak dikenal", "profit % 2", "karakter tidak terduga '%'"},
+		{"angka tidak valid", "1.2.3", "angka tidak valid"},
+		{"if kurang argumen", "if(profit > 0, 1)", "if membutuhkan 3 argumen"},
+		{"if tanpa kurung", "if + 1", "variabel 'if' tidak dikenali"},
+		{"perbandingan berantai", "1 < 2 < 3", "karakter tidak terduga '<'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseFormula(test.expression)
+			if err == nil {
+				t.Fatalf("ParseFormula(%q) tidak mengembalikan error", test.expression)
+			}
+			if !strings.Contains(err.Error(), test.message) {
+				t.Errorf("ParseFormula(%q) error = %q, want mengandung %q", test.expression, err.Error(), test.message)
+			}
+		})
+	}
+}
+
+func TestFormulaDivisionByZero(t *testing.T) {
+	formula, err := ParseFormula("profit / (stock - sold)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = formula.Evaluate(map[string]float64{"profit": 10, "stock": 5, "sold": 5})
+	if !errors.Is(err, ErrDivisionByZero) {
+		t.Fatalf("error = %v, want ErrDivisionByZero", err)
+	}
+
+	// penanganan pembagian dengan nol mengikuti DivisionByZero kriteria
+	variables := map[string]float64{"profit": 10, "stock": 5, "sold": 5}
+	zero, err := Criteria{Name: "A", Formula: "profit / (stock - sold)", DivisionByZero: DivisionByZeroAsZero}.Evaluator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := zero.Evaluate(variables); err != nil || got != 0 {
+		t.Errorf("DivisionByZeroAsZero = (%v, %v), want (0, nil)", got, err)
+	}
+
+	strict, err := Criteria{Name: "A", Formula: "profit / (stock - sold)", DivisionByZero: DivisionByZeroAsError}.Evaluator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := strict.Evaluate(variables); err == nil {
+		t.Error("DivisionByZeroAsError tidak mengembalikan error")
+	}
+}
+
+func TestValidateCriteriaFormulaLength(t *testing.T) {
+	formula := "profit" + strings.Repeat(" + 1", (MaxFormulaLength-len("profit"))/4)
+	valid := Criteria{Name: "A", Type: "benefit", Weight: 0.5, Formula: formula}
+	if err := ValidateCriteria(&valid); err != nil {
+		t.Fatalf("rumus %d karakter ditolak: %v", len(formula), err)
+	}
+
+	tooLong := Criteria{Name: "A", Type: "benefit", Weight: 0.5, Formula: formula + " + 1 + 1"}
+	if err := ValidateCriteria(&tooLong); err == nil || !strings.Contains(err.Error(), "maksimal") {
+		t.Fatalf("rumus %d karakter error = %v, want batas panjang", len(tooLong.Formula), err)
+	}
+}
+
+func TestValidateCriteriaWithoutFormula(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria Criteria
+		wantErr  bool
+	}{
+		{"kriteria baru wajib memiliki rumus", Criteria{Name: "A", Type: "Benefit", Weight: 0.5}, true},
+		{"kriteria lama tanpa rumus tetap dapat diubah", Criteria{ID: 1, Name: "A", Type: "Cost", Weight: 0.5}, false},
+		{"rumus kriteria lama yang diisi tetap diperiksa", Criteria{ID: 1, Name: "A", Type: "Cost", Weight: 0.5, Formula: "profit +"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateCriteria(&test.criteria)
+			if (err != nil) != test.wantErr {
+				t.Errorf("ValidateCriteria() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyWeightPolicyReject(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []float64
+		wantErr bool
+	}{
+		{"tepat 1", []float64{0.3, 0.4, 0.3}, false},
+		{"di bawah 1", []float64{0.3, 0.4, 0.2}, true},
+		{"di atas 1", []float64{0.3, 0.4, 0.4}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var criteriaList []Criteria
+			for _, weight := range test.weights {
+				criteriaList = append(criteriaList, Criteria{Weight: weight})
+			}
+
+			_, _, err := ApplyWeightPolicy(criteriaList, WeightPolicyReject)
+			if (err != nil) != test.wantErr || (err != nil && !errors.Is(err, ErrWeightSum)) {
+				t.Errorf("ApplyWeightPolicy() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultEfficiencyFormulaWithoutProfit(t *testing.T) {
+	// produk tanpa keuntungan dijaga oleh if pada rumus, bukan oleh penanganan pembagian dengan nol
+	evaluator, err := Criteria{Name: "Rasio Efisiensi", Formula: DefaultFormulas["rasio efisiensi"], DivisionByZero: DivisionByZeroAsError}.Evaluator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		variables map[string]float64
+		want      float64
+	}{
+		{"tanpa keuntungan", map[string]float64{"purchase_cost": 8000, "price_sale": 8000, "profit": 0, "sold": 10}, 0},
+		{"untung", map[string]float64{"purchase_cost": 8000, "price_sale": 10000, "profit": 2000, "sold": 10}, 0.8},
+		{"rugi", map[string]float64{"purchase_cost": 12000, "price_sale": 10000, "profit": -2000, "sold": 10}, 1.2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := evaluator.Evaluate(test.variables)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-test.want) > 1e-9 {
+				t.Errorf("rasio efisiensi = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
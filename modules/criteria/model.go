@@ -1,21 +1,138 @@
 package criteria
 
 import (
+	"backend-profitrack/modules/product"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 )
 
 type Criteria struct {
-	ID        int       `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
-	Name      string    `gorm:"type:varchar(25);UNIQUE;not null" json:"name"`
-	Weight    float64   `gorm:"double;not null" json:"weight"`
-	Type      string    `gorm:"varchar(25);not null" json:"type"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID             int       `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	Name           string    `gorm:"type:varchar(25);UNIQUE;not null" json:"name"`
+	Weight         float64   `gorm:"double;not null" json:"weight"`
+	Type           string    `gorm:"varchar(25);not null" json:"type"`
+	Formula        string    `gorm:"type:varchar(255)" json:"formula"`
+	DivisionByZero string    `gorm:"type:varchar(10);default:'zero'" json:"division_by_zero"`
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// MaxFormulaLength sama dengan panjang kolom formula (varchar(255))
+const MaxFormulaLength = 255
+
+// Penanganan pembagian dengan nol pada rumus kriteria
+const (
+	DivisionByZeroAsZero  = "zero"
+	DivisionByZeroAsError = "error"
+)
+
+// RequestCriteria adalah body POST/PUT kriteria, Weights berisi bobot baru kriteria lain (ID -> bobot)
+// yang disimpan bersamaan sehingga total bobot tetap 1 saat bobot diubah dengan kebijakan reject
+type RequestCriteria struct {
+	Criteria
+	Weights map[int]float64 `json:"weights"`
 }
 
 type ResponseCriteria struct {
-	ID     int     ` json:"id"`
-	Name   string  `json:"name"`
-	Weight float64 ` json:"weight"`
-	Type   string  `json:"type"`
+	ID             int     ` json:"id"`
+	Name           string  `json:"name"`
+	Weight         float64 ` json:"weight"`
+	Type           string  `json:"type"`
+	Formula        string  `json:"formula"`
+	DivisionByZero string  `json:"division_by_zero"`
+}
+
+// Evaluate menghitung nilai kriteria sebuah produk dari rumus kriteria, untuk banyak produk gunakan Evaluator
+// agar rumus hanya diurai sekali
+func (c Criteria) Evaluate(figures product.Figures) (float64, error) {
+	evaluator, err := c.Evaluator()
+	if err != nil {
+		return 0, err
+	}
+	return evaluator.Evaluate(figures.Variables())
+}
+
+// Evaluator adalah rumus kriteria yang sudah diurai beserta penanganan pembagian dengan nolnya
+type Evaluator struct {
+	criteria Criteria
+	formula  *Formula
+}
+
+func (c Criteria) Evaluator() (Evaluator, error) {
+	if c.Formula == "" {
+		return Evaluator{}, fmt.Errorf("kriteria %s belum memiliki rumus", c.Name)
+	}
+
+	formula, err := ParseFormula(c.Formula)
+	if err != nil {
+		return Evaluator{}, fmt.Errorf("rumus kriteria %s tidak valid: %v", c.Name, err)
+	}
+	return Evaluator{criteria: c, formula: formula}, nil
+}
+
+// Evaluate menghitung nilai kriteria dari variabel sebuah produk (product.Figures.Variables)
+func (e Evaluator) Evaluate(variables map[string]float64) (float64, error) {
+	value, err := e.formula.Evaluate(variables)
+	if errors.Is(err, ErrDivisionByZero) {
+		if e.criteria.DivisionByZero == DivisionByZeroAsError {
+			return 0, fmt.Errorf("rumus kriteria %s menghasilkan pembagian dengan nol", e.criteria.Name)
+		}
+		return 0, nil
+	}
+	return value, err
+}
+
+// SnapshotItem adalah bobot, tipe dan rumus sebuah kriteria pada saat data lain (perhitungan, laporan) dibuat.
+// Snapshot lama yang dibuat sebelum ada rumus memiliki Formula dan DivisionByZero kosong
+type SnapshotItem struct {
+	ID             int     `json:"id"`
+	Name           string  `json:"name"`
+	Weight         float64 `json:"weight"`
+	Type           string  `json:"type"`
+	Formula        string  `json:"formula"`
+	DivisionByZero string  `json:"division_by_zero"`
+}
+
+// Snapshot disimpan sebagai kolom jsonb
+type Snapshot []SnapshotItem
+
+func NewSnapshot(criteriaList []Criteria) Snapshot {
+	snapshot := make(Snapshot, 0, len(criteriaList))
+	for _, criteria := range criteriaList {
+		snapshot = append(snapshot, SnapshotItem{
+			ID:             criteria.ID,
+			Name:           criteria.Name,
+			Weight:         criteria.Weight,
+			Type:           criteria.Type,
+			Formula:        criteria.Formula,
+			DivisionByZero: criteria.DivisionByZero,
+		})
+	}
+	return snapshot
+}
+
+func (s Snapshot) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+
+	value, err := json.Marshal(s)
+	return string(value), err
+}
+
+func (s *Snapshot) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, s)
+	case string:
+		return json.Unmarshal([]byte(data), s)
+	default:
+		return fmt.Errorf("tipe data snapshot kriteria tidak didukung: %T", value)
+	}
 }
@@ -13,6 +13,15 @@ type Repository interface {
 	GetCriteriaByIdRepository(criteriaID int) (criteria Criteria, err error)
 	UpdateCriteriaRepository(criteria *Criteria) (err error)
 	DeleteCriteriaRepository(criteria *Criteria) (err error)
+	SaveAllCriteriaRepository(criteriaList []Criteria) (err error)
+}
+
+// DefaultFormulas adalah rumus kriteria bawaan berdasarkan nama kriteria. Rasio efisiensi produk tanpa
+// keuntungan bernilai 0, sama seperti perhitungan sebelum ada rumus
+var DefaultFormulas = map[string]string{
+	"return on investment": "(profit * sold) / (purchase_cost * stock)",
+	"net profit margin":    "(profit * sold) / (price_sale * sold)",
+	"rasio efisiensi":      "if(profit == 0, 0, (purchase_cost * sold) / (sold * price_sale))",
 }
 
 type criteriaRepository struct {
@@ -26,25 +35,31 @@ func NewCriteriaRepository(db *gorm.DB) Repository {
 	if count == 0 {
 		criteriaList := []Criteria{
 			{
-				Name:      "Return On Investment",
-				Type:      "Benefit",
-				Weight:    0.3,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
+				Name:           "Return On Investment",
+				Type:           "Benefit",
+				Weight:         0.3,
+				Formula:        DefaultFormulas["return on investment"],
+				DivisionByZero: DivisionByZeroAsZero,
+				CreatedAt:      time.Now(),
+				UpdatedAt:      time.Now(),
 			},
 			{
-				Name:      "Net Profit Margin",
-				Type:      "Benefit",
-				Weight:    0.4,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
+				Name:           "Net Profit Margin",
+				Type:           "Benefit",
+				Weight:         0.4,
+				Formula:        DefaultFormulas["net profit margin"],
+				DivisionByZero: DivisionByZeroAsZero,
+				CreatedAt:      time.Now(),
+				UpdatedAt:      time.Now(),
 			},
 			{
-				Name:      "Rasio Efisiensi",
-				Type:      "Cost",
-				Weight:    0.3,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
+				Name:           "Rasio Efisiensi",
+				Type:           "Cost",
+				Weight:         0.3,
+				Formula:        DefaultFormulas["rasio efisiensi"],
+				DivisionByZero: DivisionByZeroAsZero,
+				CreatedAt:      time.Now(),
+				UpdatedAt:      time.Now(),
 			},
 		}
 
@@ -88,3 +103,16 @@ func (r *criteriaRepository) DeleteCriteriaRepository(criteria *Criteria) (err e
 	err = r.DB.Delete(criteria).Error
 	return err
 }
+
+// SaveAllCriteriaRepository menyimpan beberapa kriteria sekaligus (misalnya setelah bobot dinormalisasi)
+// dalam satu transaksi, kriteria dengan ID 0 akan dibuat
+func (r *criteriaRepository) SaveAllCriteriaRepository(criteriaList []Criteria) (err error) {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range criteriaList {
+			if err = tx.Save(&criteriaList[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
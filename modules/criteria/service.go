@@ -1,13 +1,16 @@
 package criteria
 
 import (
+	"backend-profitrack/config"
 	"backend-profitrack/helpers"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,6 +18,7 @@ type Service interface {
 	CountCriteriaService(ctx *gin.Context)
 	GetAllCriteriaService(ctx *gin.Context)
 	GetCriteriaByIdService(ctx *gin.Context)
+	CreateCriteriaService(ctx *gin.Context)
 	UpdateCriteriaService(ctx *gin.Context)
 	DeleteCriteriaService(ctx *gin.Context)
 }
@@ -51,10 +55,12 @@ func (service *criteriaService) GetAllCriteriaService(ctx *gin.Context) {
 	var result []ResponseCriteria
 	for _, criteria := range criterias {
 		result = append(result, ResponseCriteria{
-			ID:     criteria.ID,
-			Name:   criteria.Name,
-			Weight: criteria.Weight,
-			Type:   criteria.Type,
+			ID:             criteria.ID,
+			Name:           criteria.Name,
+			Weight:         criteria.Weight,
+			Type:           criteria.Type,
+			Formula:        criteria.Formula,
+			DivisionByZero: criteria.DivisionByZero,
 		})
 	}
 
@@ -95,8 +101,32 @@ func (service *criteriaService) GetCriteriaByIdService(ctx *gin.Context) {
 	helpers.ResponseJSON(ctx, http.StatusOK, criteria)
 }
 
+func (service *criteriaService) CreateCriteriaService(ctx *gin.Context) {
+	var request RequestCriteria
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	newCriteria := request.Criteria
+	newCriteria.ID = 0
+	newCriteria.CreatedAt = time.Now()
+	newCriteria.UpdatedAt = time.Now()
+
+	statusCode, err := service.saveCriteria(&newCriteria, request.Weights)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusCreated, newCriteria)
+}
+
 func (service *criteriaService) UpdateCriteriaService(ctx *gin.Context) {
-	var criteriaUpdate Criteria
+	var request RequestCriteria
 
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
@@ -105,11 +135,12 @@ func (service *criteriaService) UpdateCriteriaService(ctx *gin.Context) {
 		return
 	}
 
-	if err = ctx.ShouldBindJSON(&criteriaUpdate); err != nil {
+	if err = ctx.ShouldBindJSON(&request); err != nil {
 		response := map[string]string{"error": "failed to read json"}
 		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
 		return
 	}
+	criteriaUpdate := request.Criteria
 
 	existingCriteria, err := service.repository.GetCriteriaByIdRepository(id)
 	if err != nil {
@@ -142,6 +173,27 @@ func (service *criteriaService) UpdateCriteriaService(ctx *gin.Context) {
 		}
 	}
 
+	// Update formula if provided and different
+	if criteriaUpdate.Formula != "" {
+		if criteriaUpdate.Formula != existingCriteria.Formula {
+			existingCriteria.Formula = criteriaUpdate.Formula
+			hasChanges = true
+		}
+	}
+
+	// Update division by zero handling if provided and different
+	if criteriaUpdate.DivisionByZero != "" {
+		if criteriaUpdate.DivisionByZero != existingCriteria.DivisionByZero {
+			existingCriteria.DivisionByZero = criteriaUpdate.DivisionByZero
+			hasChanges = true
+		}
+	}
+
+	// Weights of other criteria count as a change
+	if len(request.Weights) > 0 {
+		hasChanges = true
+	}
+
 	// Check if any changes were made
 	if !hasChanges {
 		response := map[string]string{"error": "Tidak ada perubahan data"}
@@ -151,10 +203,10 @@ func (service *criteriaService) UpdateCriteriaService(ctx *gin.Context) {
 
 	existingCriteria.UpdatedAt = time.Now()
 
-	err = service.repository.UpdateCriteriaRepository(&existingCriteria)
+	statusCode, err := service.saveCriteria(&existingCriteria, request.Weights)
 	if err != nil {
-		response := map[string]string{"error": "Gagal mengubah data kriteria"}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
 		return
 	}
 
@@ -162,6 +214,85 @@ func (service *criteriaService) UpdateCriteriaService(ctx *gin.Context) {
 	helpers.ResponseJSON(ctx, http.StatusOK, response)
 }
 
+// saveCriteria memvalidasi kriteria lalu menyimpannya bersama bobot baru kriteria lain (weights) sesuai
+// kebijakan total bobot. Dengan kebijakan normalize seluruh bobot disesuaikan ulang, dengan kebijakan reject
+// setiap perubahan bobot harus menghasilkan total tepat 1. Perubahan tanpa bobot (tipe, rumus) tidak memeriksa
+// total sehingga data lama tetap dapat diubah, perhitungan tetap ditolak selama totalnya belum 1
+func (service *criteriaService) saveCriteria(target *Criteria, weights map[int]float64) (statusCode int, err error) {
+	if err = ValidateCriteria(target); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	criteriaList, err := service.repository.GetAllCriteriaRepository()
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("gagal mengambil data kriteria")
+	}
+
+	// daftar kriteria setelah perubahan disimpan
+	targetIndex := -1
+	weightChanged := target.ID == 0 || len(weights) > 0
+	applied := make(map[int]bool, len(weights))
+	var updatedList []Criteria
+	for _, criteria := range criteriaList {
+		if target.ID != 0 && criteria.ID == target.ID {
+			weightChanged = weightChanged || criteria.Weight != target.Weight
+			targetIndex = len(updatedList)
+			updatedList = append(updatedList, *target)
+			continue
+		}
+
+		if weight, exists := weights[criteria.ID]; exists {
+			if weight <= 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+				return http.StatusBadRequest, fmt.Errorf("bobot kriteria %s harus lebih dari 0", criteria.Name)
+			}
+			criteria.Weight = weight
+			criteria.UpdatedAt = time.Now()
+			applied[criteria.ID] = true
+		}
+		updatedList = append(updatedList, criteria)
+	}
+	if targetIndex == -1 {
+		targetIndex = len(updatedList)
+		updatedList = append(updatedList, *target)
+	}
+
+	for criteriaID := range weights {
+		if criteriaID == target.ID {
+			return http.StatusBadRequest, fmt.Errorf("bobot kriteria yang diubah diisi lewat weight, bukan weights")
+		}
+		if _, exists := applied[criteriaID]; !exists {
+			return http.StatusBadRequest, fmt.Errorf("Kriteria dengan ID:%d pada weights tidak ditemukan", criteriaID)
+		}
+	}
+
+	saveList := updatedList
+	switch {
+	case config.CriteriaWeightPolicy() == WeightPolicyNormalize:
+		saveList, _, err = ApplyWeightPolicy(updatedList, WeightPolicyNormalize)
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+	case weightChanged:
+		if _, _, err = ApplyWeightPolicy(updatedList, WeightPolicyReject); err != nil {
+			return http.StatusBadRequest, fmt.Errorf("%v, sertakan bobot kriteria lain pada weights", err)
+		}
+	default:
+		saveList = updatedList[targetIndex : targetIndex+1]
+		targetIndex = 0
+	}
+
+	err = service.repository.SaveAllCriteriaRepository(saveList)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+			return http.StatusBadRequest, fmt.Errorf("nama kriteria sudah ada")
+		}
+		return http.StatusInternalServerError, fmt.Errorf("gagal menyimpan data kriteria")
+	}
+
+	*target = saveList[targetIndex]
+	return http.StatusOK, nil
+}
+
 func (service *criteriaService) DeleteCriteriaService(ctx *gin.Context) {
 	var criteria Criteria
 	id, err := strconv.Atoi(ctx.Param("id"))
@@ -0,0 +1,95 @@
+package criteria
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kebijakan total bobot kriteria, lihat config.CriteriaWeightPolicy
+const (
+	WeightPolicyReject    = "reject"
+	WeightPolicyNormalize = "normalize"
+)
+
+// AllowedTypes adalah tipe kriteria yang dikenali oleh metode perhitungan
+var AllowedTypes = []string{"Benefit", "Cost"}
+
+// ErrWeightSum dikembalikan jika total bobot kriteria tidak sesuai kebijakan
+var ErrWeightSum = errors.New("total bobot kriteria harus 1")
+
+// weightTolerance adalah toleransi pembulatan saat membandingkan total bobot dengan 1
+const weightTolerance = 1e-6
+
+// ValidateCriteria memeriksa satu kriteria sebelum disimpan dan menyeragamkan penulisan tipenya
+func ValidateCriteria(criteria *Criteria) error {
+	criteria.Name = strings.TrimSpace(criteria.Name)
+	if criteria.Name == "" {
+		return fmt.Errorf("nama kriteria tidak boleh kosong")
+	}
+
+	validType := false
+	for _, allowedType := range AllowedTypes {
+		if strings.EqualFold(criteria.Type, allowedType) {
+			criteria.Type = allowedType
+			validType = true
+		}
+	}
+	if !validType {
+		return fmt.Errorf("tipe kriteria harus salah satu dari %s", strings.Join(AllowedTypes, ", "))
+	}
+
+	if criteria.Weight <= 0 || math.IsNaN(criteria.Weight) || math.IsInf(criteria.Weight, 0) {
+		return fmt.Errorf("bobot kriteria harus lebih dari 0")
+	}
+
+	if utf8.RuneCountInString(criteria.Formula) > MaxFormulaLength {
+		return fmt.Errorf("rumus kriteria maksimal %d karakter", MaxFormulaLength)
+	}
+
+	// kriteria lama yang dibuat sebelum ada rumus tetap dapat diubah bobot atau tipenya
+	if criteria.ID == 0 || criteria.Formula != "" {
+		if _, err := ParseFormula(criteria.Formula); err != nil {
+			return fmt.Errorf("rumus tidak valid: %v", err)
+		}
+	}
+
+	if criteria.DivisionByZero == "" {
+		criteria.DivisionByZero = DivisionByZeroAsZero
+	}
+	if criteria.DivisionByZero != DivisionByZeroAsZero && criteria.DivisionByZero != DivisionByZeroAsError {
+		return fmt.Errorf("division_by_zero harus bernilai %s atau %s", DivisionByZeroAsZero, DivisionByZeroAsError)
+	}
+
+	return nil
+}
+
+func TotalWeight(criteriaList []Criteria) float64 {
+	total := 0.0
+	for _, criteria := range criteriaList {
+		total += criteria.Weight
+	}
+	return total
+}
+
+// ApplyWeightPolicy memastikan total bobot sama dengan 1. Dengan kebijakan normalize bobot dibagi
+// dengan totalnya dan changed bernilai true jika ada bobot yang berubah
+func ApplyWeightPolicy(criteriaList []Criteria, policy string) (result []Criteria, changed bool, err error) {
+	total := TotalWeight(criteriaList)
+	if math.Abs(total-1) <= weightTolerance {
+		return criteriaList, false, nil
+	}
+
+	if policy != WeightPolicyNormalize || total <= 0 {
+		return criteriaList, false, fmt.Errorf("%w, saat ini %.4f", ErrWeightSum, total)
+	}
+
+	result = make([]Criteria, len(criteriaList))
+	for i, criteria := range criteriaList {
+		criteria.Weight = criteria.Weight / total
+		result[i] = criteria
+	}
+	return result, true, nil
+}
@@ -6,10 +6,13 @@ import (
 	"time"
 )
 
+// CriteriaScore.PeriodID 0 berarti nilai dihitung dari data produk saat ini, selain itu dari angka produk
+// pada periode tersebut
 type CriteriaScore struct {
 	ID         int               `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
-	ProductID  int               `gorm:"smallint;not null" json:"product_id"`
-	CriteriaID int               `gorm:"smallint;not null" json:"criteria_id"`
+	PeriodID   int               `gorm:"integer;not null;default:0;uniqueIndex:idx_criteria_scores_period_product_criteria" json:"period_id"`
+	ProductID  int               `gorm:"smallint;not null;uniqueIndex:idx_criteria_scores_period_product_criteria" json:"product_id"`
+	CriteriaID int               `gorm:"smallint;not null;uniqueIndex:idx_criteria_scores_period_product_criteria" json:"criteria_id"`
 	Score      float64           `gorm:"double" json:"score"`
 	Product    product.Product   `gorm:"foreignkey:ProductID" json:"-"`
 	Criteria   criteria.Criteria `gorm:"foreignkey:CriteriaID" json:"-"`
@@ -1,15 +1,22 @@
 package criteria_score
 
-import "gorm.io/gorm"
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 type Repository interface {
-	GetAllCriteriaScoreRepository() (result []CriteriaScore, err error)
+	GetAllCriteriaScoreRepository(periodID int) (result []CriteriaScore, err error)
 	GetCriteriaScoreByProductIdRepository(productID int) (criteriaScores []CriteriaScore, err error)
 	CreateCriteriaScoreRepository(criteriaScore *CriteriaScore) (err error)
 	UpdateCriteriaScoreRepository(criteriaScore *CriteriaScore) (err error)
+	UpsertCriteriaScoresRepository(criteriaScores []CriteriaScore) (err error)
 	DeleteCriteriaScoreRepository(productID int) (err error)
 }
 
+// batchSize adalah jumlah baris per INSERT saat menyimpan nilai kriteria
+const batchSize = 500
+
 type criteriaScoreRepository struct {
 	DB *gorm.DB
 }
@@ -20,8 +27,9 @@ func NewCriteriaScoreRepository(db *gorm.DB) Repository {
 	}
 }
 
-func (r *criteriaScoreRepository) GetAllCriteriaScoreRepository() (result []CriteriaScore, err error) {
-	err = r.DB.Order("product_id ASC, criteria_id ASC").Find(&result).Error
+// GetAllCriteriaScoreRepository mengambil nilai kriteria satu periode, periodID 0 untuk data produk saat ini
+func (r *criteriaScoreRepository) GetAllCriteriaScoreRepository(periodID int) (result []CriteriaScore, err error) {
+	err = r.DB.Where("period_id = ?", periodID).Order("product_id ASC, criteria_id ASC").Find(&result).Error
 	return result, err
 }
 
@@ -40,6 +48,21 @@ func (r *criteriaScoreRepository) UpdateCriteriaScoreRepository(criteriaScore *C
 	return err
 }
 
+// UpsertCriteriaScoresRepository menyimpan nilai kriteria per batch, nilai untuk pasangan produk-kriteria
+// pada periode yang sama yang sudah ada diperbarui (unique index period_id, product_id, criteria_id)
+func (r *criteriaScoreRepository) UpsertCriteriaScoresRepository(criteriaScores []CriteriaScore) (err error) {
+	if len(criteriaScores) == 0 {
+		return nil
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "period_id"}, {Name: "product_id"}, {Name: "criteria_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"score", "updated_at"}),
+		}).CreateInBatches(&criteriaScores, batchSize).Error
+	})
+}
+
 func (r *criteriaScoreRepository) DeleteCriteriaScoreRepository(productID int) (err error) {
 	err = r.DB.Where("product_id = ?", productID).Delete(&CriteriaScore{}).Error
 	return err
@@ -3,6 +3,7 @@ package criteria_score
 import (
 	"backend-profitrack/middleware"
 	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/period"
 	"backend-profitrack/modules/product"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -12,7 +13,8 @@ func Initiator(router *gin.Engine, db *gorm.DB) {
 	repo := NewCriteriaScoreRepository(db)
 	criteriaRepo := criteria.NewCriteriaRepository(db)
 	productRepo := product.NewProductRepository(db)
-	service := NewCriteriaScoreService(repo, criteriaRepo, productRepo)
+	periodRepo := period.NewPeriodRepository(db)
+	service := NewCriteriaScoreService(repo, criteriaRepo, productRepo, periodRepo)
 
 	api := router.Group("/api")
 	api.Use(middleware.LoggingMiddleware())
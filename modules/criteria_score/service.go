@@ -3,6 +3,7 @@ package criteria_score
 import (
 	"backend-profitrack/helpers"
 	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/period"
 	"backend-profitrack/modules/product"
 	"errors"
 	"fmt"
@@ -10,7 +11,6 @@ import (
 	"gorm.io/gorm"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -25,19 +25,30 @@ type criteriaScoreService struct {
 	repository         Repository
 	criteriaRepository criteria.Repository
 	productRepository  product.Repository
+	periodRepository   period.Repository
 }
 
-func NewCriteriaScoreService(repo Repository, criteriaRepo criteria.Repository, productRepo product.Repository) Service {
+func NewCriteriaScoreService(repo Repository, criteriaRepo criteria.Repository, productRepo product.Repository, periodRepo period.Repository) Service {
 	return &criteriaScoreService{
 		repo,
 		criteriaRepo,
 		productRepo,
+		periodRepo,
 	}
 }
 
+// CreateAllCriteriaScoreService menghitung nilai semua produk dari data produk saat ini, query opsional
+// period_id membuat nilai dihitung dari angka produk periode dan disimpan terpisah untuk periode tersebut
 func (service *criteriaScoreService) CreateAllCriteriaScoreService(ctx *gin.Context) {
 	var response map[string]string
-	existingScores, err := service.repository.GetAllCriteriaScoreRepository()
+	getPeriod, statusCode, err := period.Resolve(service.periodRepository, ctx.Query("period_id"))
+	if err != nil {
+		response = map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	existingScores, err := service.repository.GetAllCriteriaScoreRepository(getPeriod.ID)
 	if err != nil {
 		response = map[string]string{"error": "gagal mengambil data nilai kriteria"}
 		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
@@ -76,58 +87,42 @@ func (service *criteriaScoreService) CreateAllCriteriaScoreService(ctx *gin.Cont
 		return
 	}
 
-	for _, produk := range productList {
-		for _, kriteria := range criteriaList {
-			var nilai float64
-			purchaseCost := float64(produk.PurchaseCost)
-			priceSale := float64(produk.PriceSale)
-			profit := float64(produk.Profit)
-			stock := float64(produk.Stock)
-			sold := float64(produk.Sold)
-
-			switch strings.ToLower(kriteria.Name) {
-			case strings.ToLower("Return On Investment"):
-				if produk.PurchaseCost != 0 {
-					nilai = (profit * sold) / (purchaseCost * stock)
-				}
-			case strings.ToLower("Net Profit Margin"):
-				if produk.PriceSale != 0 {
-					nilai = (profit * sold) / (priceSale * sold)
-				}
-			case strings.ToLower("Rasio Efisiensi"):
-				if produk.Profit != 0 {
-					nilai = (purchaseCost * sold) / (sold * priceSale)
-				}
-			default:
-				response := map[string]string{"error": "kriteria tidak dikenali"}
-				helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
-				return
-			}
+	figures, statusCode, err := service.productFigures(ctx, getPeriod, productList)
+	if err != nil {
+		response = map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
 
-			newScore := CriteriaScore{
-				ProductID:  produk.ID,
-				CriteriaID: kriteria.ID,
-				Score:      nilai,
-				CreatedAt:  time.Now(),
-				UpdatedAt:  time.Now(),
-			}
+	newScores, err := evaluateScores(getPeriod.ID, productList, figures, criteriaList)
+	if err != nil {
+		response = map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
 
-			err = service.repository.CreateCriteriaScoreRepository(&newScore)
-			if err != nil {
-				response = map[string]string{"error": "gagal menyimpan data nilai untuk produk " + produk.Name}
-				helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
-				return
-			}
-		}
+	err = service.repository.UpsertCriteriaScoresRepository(newScores)
+	if err != nil {
+		response = map[string]string{"error": "gagal menyimpan data nilai kriteria"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
 	}
 
 	response = map[string]string{"message": "nilai produk berhasil dihitung untuk semua kriteria dan disimpan"}
 	helpers.ResponseJSON(ctx, http.StatusCreated, response)
 }
 
+// GetAllCriteriaScoreService menerima query period_id, tanpa period_id menampilkan nilai dari data produk saat ini
 func (service *criteriaScoreService) GetAllCriteriaScoreService(ctx *gin.Context) {
 	var response map[string]string
-	values, err := service.repository.GetAllCriteriaScoreRepository()
+	getPeriod, statusCode, err := period.Resolve(service.periodRepository, ctx.Query("period_id"))
+	if err != nil {
+		response = map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	values, err := service.repository.GetAllCriteriaScoreRepository(getPeriod.ID)
 	if err != nil {
 		response = map[string]string{"error": "gagal mengambil data nilai kriteria"}
 		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
@@ -138,6 +133,7 @@ func (service *criteriaScoreService) GetAllCriteriaScoreService(ctx *gin.Context
 	for _, value := range values {
 		result = append(result, CriteriaScore{
 			ID:         value.ID,
+			PeriodID:   value.PeriodID,
 			Score:      value.Score,
 			ProductID:  value.ProductID,
 			CriteriaID: value.CriteriaID,
@@ -155,8 +151,16 @@ func (service *criteriaScoreService) GetAllCriteriaScoreService(ctx *gin.Context
 	helpers.ResponseJSON(ctx, http.StatusOK, result)
 }
 
+// UpdateCriteriaScoreService menerima query period_id yang sama dengan CreateAllCriteriaScoreService
 func (service *criteriaScoreService) UpdateCriteriaScoreService(ctx *gin.Context) {
 	var response map[string]string
+	getPeriod, statusCode, err := period.Resolve(service.periodRepository, ctx.Query("period_id"))
+	if err != nil {
+		response = map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
 	criteriaList, err := service.criteriaRepository.GetAllCriteriaRepository()
 	if err != nil {
 		response = map[string]string{"error": "gagal mengambil data kriteria"}
@@ -171,94 +175,27 @@ func (service *criteriaScoreService) UpdateCriteriaScoreService(ctx *gin.Context
 		return
 	}
 
-	existingScores, err := service.repository.GetAllCriteriaScoreRepository()
+	figures, statusCode, err := service.productFigures(ctx, getPeriod, productList)
 	if err != nil {
-		response = map[string]string{"error": "gagal mengambil data nilai kriteria"}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		response = map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
 		return
 	}
 
-	scoreMap := make(map[int]map[int]bool) // productID -> criteriaID -> exists
-	for _, score := range existingScores {
-		if _, exists := scoreMap[score.ProductID]; !exists {
-			scoreMap[score.ProductID] = make(map[int]bool)
-		}
-		scoreMap[score.ProductID][score.CriteriaID] = true
-	}
-
-	// Pastikan semua produk memiliki nilai, jika tidak maka buat nilai baru
-	for _, product := range productList {
-		for _, criteria := range criteriaList {
-			if !scoreMap[product.ID][criteria.ID] {
-				var nilai float64
-				purchaseCost := float64(product.PurchaseCost)
-				priceSale := float64(product.PriceSale)
-				profit := float64(product.Profit)
-				stock := float64(product.Stock)
-				sold := float64(product.Sold)
-
-				switch strings.ToLower(criteria.Name) {
-				case "return on investment":
-					if product.PurchaseCost != 0 {
-						nilai = (profit * sold) / (purchaseCost * stock)
-					}
-				case "net profit margin":
-					if product.PriceSale != 0 {
-						nilai = (profit * sold) / (priceSale * sold)
-					}
-				case "rasio efisiensi":
-					if product.Profit != 0 {
-						nilai = (purchaseCost * sold) / (sold * priceSale)
-					}
-				}
-
-				newScore := CriteriaScore{
-					ProductID:  product.ID,
-					CriteriaID: criteria.ID,
-					Score:      nilai,
-					CreatedAt:  time.Now(),
-					UpdatedAt:  time.Now(),
-				}
-
-				err = service.repository.CreateCriteriaScoreRepository(&newScore)
-				if err != nil {
-					response = map[string]string{"error": "gagal menyimpan data nilai untuk produk " + product.Name}
-					helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
-					return
-				}
-			}
-		}
+	// nilai semua pasangan produk-kriteria dihitung ulang di memori lalu disimpan sekaligus,
+	// pasangan yang belum ada ditambahkan dan yang sudah ada diperbarui
+	newScores, err := evaluateScores(getPeriod.ID, productList, figures, criteriaList)
+	if err != nil {
+		response = map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
 	}
 
-	// Update nilai yang sudah ada
-	for _, score := range existingScores {
-		var nilai float64
-		produk, _ := service.productRepository.GetProductByIdRepository(score.ProductID)
-		kriteria, _ := service.criteriaRepository.GetCriteriaByIdRepository(score.CriteriaID)
-
-		switch strings.ToLower(kriteria.Name) {
-		case "return on investment":
-			if produk.PurchaseCost != 0 {
-				nilai = (float64(produk.Profit) * float64(produk.Sold)) / (float64(produk.PurchaseCost) * float64(produk.Stock))
-			}
-		case "net profit margin":
-			if produk.PriceSale != 0 {
-				nilai = (float64(produk.Profit) * float64(produk.Sold)) / (float64(produk.PriceSale) * float64(produk.Sold))
-			}
-		case "rasio efisiensi":
-			if produk.Profit != 0 {
-				nilai = (float64(produk.PurchaseCost) * float64(produk.Sold)) / (float64(produk.Sold) * float64(produk.PriceSale))
-			}
-		}
-
-		score.Score = nilai
-		score.UpdatedAt = time.Now()
-		err = service.repository.UpdateCriteriaScoreRepository(&score)
-		if err != nil {
-			response = map[string]string{"error": "gagal memperbarui data nilai"}
-			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
-			return
-		}
+	err = service.repository.UpsertCriteriaScoresRepository(newScores)
+	if err != nil {
+		response = map[string]string{"error": "gagal memperbarui data nilai"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
 	}
 
 	response = map[string]string{"message": "nilai produk berhasil diperbarui atau ditambahkan jika belum ada"}
@@ -308,3 +245,77 @@ func (service *criteriaScoreService) DeleteCriteriaScoreService(ctx *gin.Context
 	response = map[string]string{"message": fmt.Sprintf("data nilai kriteria produk:%s berhasil dihapus", product.Name)}
 	helpers.ResponseJSON(ctx, http.StatusOK, response)
 }
+
+// productFigures mengembalikan angka setiap produk dari angka produk periode jika getPeriod diisi, atau dari
+// data produk saat ini. Rentang tanggal from-to ditolak karena nilainya akan tersimpan sebagai nilai data saat
+// ini, penjualan pada rentang tertentu diambil lewat periode (/periods/:id/capture) agar nilai, riwayat
+// perhitungan dan laporan memakai angka yang sama
+func (service *criteriaScoreService) productFigures(ctx *gin.Context, getPeriod period.Period, productList []product.Product) (map[int]product.Figures, int, error) {
+	if ctx.Query("from") != "" || ctx.Query("to") != "" {
+		return nil, http.StatusBadRequest, errors.New("from dan to tidak didukung, buat periode dengan rentang tersebut lalu isi angkanya lewat /periods/:id/capture dan gunakan period_id")
+	}
+
+	if getPeriod.ID != 0 {
+		periodFigures, err := service.periodRepository.GetFiguresByPeriodIdRepository(getPeriod.ID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.New("gagal mengambil data angka produk periode")
+		}
+
+		if len(periodFigures) == 0 {
+			return nil, http.StatusBadRequest, fmt.Errorf("angka produk periode %s masih kosong, isi lewat /periods/%d/capture atau /periods/%d/figures", getPeriod.Name, getPeriod.ID, getPeriod.ID)
+		}
+
+		figures := make(map[int]product.Figures, len(periodFigures))
+		for _, figure := range periodFigures {
+			figures[figure.ProductID] = figure.Figures()
+		}
+		return figures, http.StatusOK, nil
+	}
+
+	figures := make(map[int]product.Figures, len(productList))
+	for _, produk := range productList {
+		figures[produk.ID] = produk.Figures()
+	}
+	return figures, http.StatusOK, nil
+}
+
+// evaluateScores menghitung nilai setiap produk untuk setiap kriteria dari rumus kriteria,
+// produk yang tidak memiliki angka (mis. belum ada pada periode tersebut) dilewati
+func evaluateScores(periodID int, productList []product.Product, figures map[int]product.Figures, criteriaList []criteria.Criteria) ([]CriteriaScore, error) {
+	// rumus setiap kriteria diurai sekali, bukan sekali per produk
+	evaluators := make([]criteria.Evaluator, len(criteriaList))
+	for i, kriteria := range criteriaList {
+		evaluator, err := kriteria.Evaluator()
+		if err != nil {
+			return nil, err
+		}
+		evaluators[i] = evaluator
+	}
+
+	now := time.Now()
+	scores := make([]CriteriaScore, 0, len(productList)*len(criteriaList))
+	for _, produk := range productList {
+		productFigures, exists := figures[produk.ID]
+		if !exists {
+			continue
+		}
+
+		variables := productFigures.Variables()
+		for i, kriteria := range criteriaList {
+			nilai, err := evaluators[i].Evaluate(variables)
+			if err != nil {
+				return nil, fmt.Errorf("produk %s: %v", produk.Name, err)
+			}
+
+			scores = append(scores, CriteriaScore{
+				PeriodID:   periodID,
+				ProductID:  produk.ID,
+				CriteriaID: kriteria.ID,
+				Score:      nilai,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			})
+		}
+	}
+	return scores, nil
+}
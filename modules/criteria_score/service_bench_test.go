@@ -0,0 +1,42 @@
+package criteria_score
+
+import (
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/product"
+	"math/rand"
+	"testing"
+)
+
+func BenchmarkEvaluateScores10kProducts(b *testing.B) {
+	criteriaList := []criteria.Criteria{
+		{ID: 1, Name: "Return On Investment", Type: "Benefit", Weight: 0.3, Formula: "(profit * sold) / (purchase_cost * stock)", DivisionByZero: criteria.DivisionByZeroAsZero},
+		{ID: 2, Name: "Net Profit Margin", Type: "Benefit", Weight: 0.4, Formula: "(profit * sold) / (price_sale * sold)", DivisionByZero: criteria.DivisionByZeroAsZero},
+		{ID: 3, Name: "Rasio Efisiensi", Type: "Cost", Weight: 0.3, Formula: "(purchase_cost * sold * profit) / (sold * price_sale * profit)", DivisionByZero: criteria.DivisionByZeroAsZero},
+	}
+
+	random := rand.New(rand.NewSource(1))
+	productList := make([]product.Product, 10000)
+	figures := make(map[int]product.Figures, len(productList))
+	for i := range productList {
+		purchaseCost := 1000 + random.Intn(9000)
+		priceSale := purchaseCost + random.Intn(5000)
+		sold := random.Intn(500)
+		productList[i] = product.Product{ID: i + 1, Name: "Produk"}
+		figures[i+1] = product.Figures{
+			PurchaseCost: float64(purchaseCost),
+			PriceSale:    float64(priceSale),
+			Profit:       float64(priceSale - purchaseCost),
+			Stock:        float64(sold + random.Intn(500)),
+			Sold:         float64(sold),
+			Revenue:      float64(priceSale * sold),
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := evaluateScores(0, productList, figures, criteriaList); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
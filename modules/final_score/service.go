@@ -38,12 +38,13 @@ func (service *newFinalScoreService) GetAllFinalScoreByMethodIDService(ctx *gin.
 	var result []FinalScore
 	for _, score := range finalScore {
 		result = append(result, FinalScore{
-			ID:         score.ID,
-			FinalScore: score.FinalScore,
-			ProductID:  score.ProductID,
-			MethodID:   score.MethodID,
-			CreatedAt:  score.CreatedAt,
-			UpdatedAt:  score.UpdatedAt,
+			ID:               score.ID,
+			FinalScore:       score.FinalScore,
+			ProductID:        score.ProductID,
+			MethodID:         score.MethodID,
+			CalculationRunID: score.CalculationRunID,
+			CreatedAt:        score.CreatedAt,
+			UpdatedAt:        score.UpdatedAt,
 		})
 	}
 
@@ -0,0 +1,123 @@
+package inventory
+
+import (
+	"backend-profitrack/modules/product"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"math"
+	"time"
+)
+
+var (
+	ErrInsufficientStock  = errors.New("stok tidak mencukupi")
+	ErrSaleNotFound       = errors.New("penjualan yang diretur tidak ditemukan")
+	ErrReturnProduct      = errors.New("produk retur berbeda dengan produk pada penjualan")
+	ErrReturnExceedsSales = errors.New("jumlah retur melebihi jumlah penjualan yang belum diretur")
+)
+
+// ApplyMovement mencatat pergerakan stok dan memperbarui stok produk di dalam transaksi tx,
+// baris produk dikunci agar pergerakan bersamaan tidak saling menimpa. Pembelian (in) dengan
+// UnitCost memperbarui harga beli produk dengan rata-rata tertimbang beserta profitnya
+func ApplyMovement(tx *gorm.DB, movement *StockMovement) error {
+	var getProduct product.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&getProduct, movement.ProductID).Error
+	if err != nil {
+		return err
+	}
+
+	balance := getProduct.Stock + movement.Change()
+	if balance < 0 {
+		return ErrInsufficientStock
+	}
+
+	updates := map[string]interface{}{
+		"stock":      balance,
+		"updated_at": time.Now(),
+	}
+	if movement.Type == TypeIn && movement.UnitCost > 0 {
+		purchaseCost := WeightedAverageCost(getProduct.Stock, getProduct.PurchaseCost, movement.Quantity, movement.UnitCost)
+		updates["purchase_cost"] = purchaseCost
+		updates["profit"] = getProduct.PriceSale - purchaseCost
+	}
+
+	err = tx.Model(&product.Product{}).Where("id = ?", getProduct.ID).Updates(updates).Error
+	if err != nil {
+		return err
+	}
+
+	movement.Balance = balance
+	movement.Product = getProduct
+	return tx.Create(movement).Error
+}
+
+// ApplyReturn mencatat retur pelanggan atas sebuah penjualan di dalam transaksi tx: stok bertambah melalui
+// ApplyMovement dan jumlah terjual produk berkurang. Baris penjualan dikunci agar total retur tidak melebihi
+// jumlah penjualan, ringkasan penjualan menghitung penjualan tersebut tanpa jumlah yang diretur
+func ApplyReturn(tx *gorm.DB, movement *StockMovement) error {
+	if movement.SaleID == nil {
+		return ErrSaleNotFound
+	}
+
+	var sale struct {
+		ProductID int
+		Quantity  int
+	}
+	err := tx.Table("sales").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("product_id, quantity").
+		Where("id = ?", *movement.SaleID).
+		Take(&sale).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrSaleNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if movement.ProductID == 0 {
+		movement.ProductID = sale.ProductID
+	}
+	if movement.ProductID != sale.ProductID {
+		return ErrReturnProduct
+	}
+
+	var returned int
+	err = tx.Model(&StockMovement{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("sale_id = ? AND type = ?", *movement.SaleID, TypeReturn).
+		Scan(&returned).Error
+	if err != nil {
+		return err
+	}
+	if returned+movement.Quantity > sale.Quantity {
+		return ErrReturnExceedsSales
+	}
+
+	if err = ApplyMovement(tx, movement); err != nil {
+		return err
+	}
+
+	return tx.Table("products").Where("id = ?", movement.ProductID).
+		Update("sold", gorm.Expr("GREATEST(sold - ?, 0)", movement.Quantity)).Error
+}
+
+// SaleStockChange menjumlahkan perubahan stok seluruh pergerakan milik sebuah penjualan (stok keluar dikurangi
+// retur), dipakai untuk membuat pergerakan penyeimbang saat penjualan dihapus
+func SaleStockChange(tx *gorm.DB, saleID int) (change int, err error) {
+	err = tx.Model(&StockMovement{}).
+		Select("COALESCE(SUM(CASE WHEN type = ? THEN -quantity ELSE quantity END), 0)", TypeOut).
+		Where("sale_id = ?", saleID).
+		Scan(&change).Error
+	return change, err
+}
+
+// WeightedAverageCost menghitung harga beli rata-rata tertimbang:
+// (stok * harga beli lama + jumlah masuk * harga beli baru) / (stok + jumlah masuk)
+func WeightedAverageCost(stock int, purchaseCost int, quantity int, unitCost int) int {
+	if stock <= 0 {
+		return unitCost
+	}
+
+	total := float64(stock)*float64(purchaseCost) + float64(quantity)*float64(unitCost)
+	return int(math.Round(total / float64(stock+quantity)))
+}
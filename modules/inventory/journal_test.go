@@ -0,0 +1,34 @@
+package inventory
+
+import "testing"
+
+func TestWeightedAverageCost(t *testing.T) {
+	tests := []struct {
+		name         string
+		stock        int
+		purchaseCost int
+		quantity     int
+		unitCost     int
+		want         int
+	}{
+		// (10 * 1000 + 10 * 2000) / 20
+		{"jumlah sama", 10, 1000, 10, 2000, 1500},
+		// (30 * 1000 + 10 * 2000) / 40
+		{"stok lebih banyak", 30, 1000, 10, 2000, 1250},
+		// 4001 / 4 = 1000.25
+		{"dibulatkan ke bawah", 3, 1000, 1, 1001, 1000},
+		// 2001 / 2 = 1000.5
+		{"dibulatkan ke atas", 1, 1000, 1, 1001, 1001},
+		{"stok kosong", 0, 1000, 5, 1200, 1200},
+		{"stok negatif", -3, 1000, 5, 1200, 1200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := WeightedAverageCost(test.stock, test.purchaseCost, test.quantity, test.unitCost)
+			if got != test.want {
+				t.Errorf("WeightedAverageCost() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package inventory
+
+import (
+	"backend-profitrack/modules/product"
+	"time"
+)
+
+const (
+	TypeIn         = "in"
+	TypeOut        = "out"
+	TypeReturn     = "return"
+	TypeAdjustment = "adjustment"
+)
+
+// StockMovement adalah satu baris jurnal stok, Quantity selalu positif kecuali untuk adjustment
+// yang boleh negatif. Balance adalah stok produk setelah pergerakan ini dicatat
+type StockMovement struct {
+	ID        int             `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	ProductID int             `gorm:"integer;not null;index" json:"product_id"`
+	Type      string          `gorm:"varchar(20);not null;index" json:"type"`
+	Quantity  int             `gorm:"integer;not null" json:"quantity"`
+	UnitCost  int             `gorm:"integer" json:"unit_cost"`
+	Balance   int             `gorm:"integer" json:"balance"`
+	Reason    string          `gorm:"varchar(255)" json:"reason"`
+	SaleID    *int            `gorm:"integer;index" json:"sale_id"`
+	UserID    int             `gorm:"integer" json:"user_id"`
+	MovedAt   time.Time       `gorm:"not null;index" json:"moved_at"`
+	Product   product.Product `gorm:"foreignkey:ProductID" json:"-"`
+	CreatedAt time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// Change adalah perubahan stok akibat pergerakan: in dan return (retur pelanggan) menambah, out mengurangi
+func (m StockMovement) Change() int {
+	switch m.Type {
+	case TypeOut:
+		return -m.Quantity
+	case TypeIn, TypeReturn, TypeAdjustment:
+		return m.Quantity
+	}
+	return 0
+}
+
+// RequestMovement: unit_cost wajib untuk pembelian, sale_id wajib untuk retur (product_id boleh kosong),
+// quantity adjustment boleh negatif, moved_at (YYYY-MM-DD) kosong memakai hari ini
+type RequestMovement struct {
+	ProductID int    `json:"product_id"`
+	SaleID    *int   `json:"sale_id"`
+	Quantity  int    `json:"quantity"`
+	UnitCost  int    `json:"unit_cost"`
+	Reason    string `json:"reason"`
+	MovedAt   string `json:"moved_at"`
+}
+
+type ResponseMovement struct {
+	ID          int       `json:"id"`
+	ProductID   int       `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Type        string    `json:"type"`
+	Quantity    int       `json:"quantity"`
+	Change      int       `json:"change"`
+	UnitCost    int       `json:"unit_cost"`
+	Balance     int       `json:"balance"`
+	Reason      string    `json:"reason"`
+	SaleID      *int      `json:"sale_id"`
+	MovedAt     time.Time `json:"moved_at"`
+}
+
+// ResponseProductMovements adalah riwayat stok satu produk, OpeningStock adalah stok sebelum
+// pergerakan pertama tercatat (stok awal saat produk dibuat)
+type ResponseProductMovements struct {
+	ProductID    int                `json:"product_id"`
+	ProductName  string             `json:"product_name"`
+	OpeningStock int                `json:"opening_stock"`
+	Stock        int                `json:"stock"`
+	PurchaseCost int                `json:"purchase_cost"`
+	Movements    []ResponseMovement `json:"movements"`
+}
@@ -0,0 +1,26 @@
+package inventory
+
+import "testing"
+
+func TestStockMovementChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		movement StockMovement
+		want     int
+	}{
+		{"pembelian menambah", StockMovement{Type: TypeIn, Quantity: 5}, 5},
+		{"penjualan mengurangi", StockMovement{Type: TypeOut, Quantity: 5}, -5},
+		{"retur menambah", StockMovement{Type: TypeReturn, Quantity: 3}, 3},
+		{"penyesuaian positif", StockMovement{Type: TypeAdjustment, Quantity: 2}, 2},
+		{"penyesuaian negatif", StockMovement{Type: TypeAdjustment, Quantity: -2}, -2},
+		{"tipe tidak dikenal", StockMovement{Type: "transfer", Quantity: 4}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.movement.Change(); got != test.want {
+				t.Errorf("Change() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package inventory
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type Repository interface {
+	GetAllMovementRepository(productID int, movementType string) (result []StockMovement, err error)
+	CreateMovementRepository(movement *StockMovement) (err error)
+	SumChangesSinceRepository(since time.Time) (result map[int]int, err error)
+}
+
+type inventoryRepository struct {
+	DB *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) Repository {
+	return &inventoryRepository{
+		DB: db,
+	}
+}
+
+// GetAllMovementRepository mengambil pergerakan stok sesuai urutan pencatatan (urutan yang sama dengan
+// kolom balance), productID 0 dan movementType kosong berarti tanpa filter
+func (r *inventoryRepository) GetAllMovementRepository(productID int, movementType string) (result []StockMovement, err error) {
+	query := r.DB.Preload("Product").Order("id ASC")
+	if productID != 0 {
+		query = query.Where("product_id = ?", productID)
+	}
+	if movementType != "" {
+		query = query.Where("type = ?", movementType)
+	}
+
+	err = query.Find(&result).Error
+	return result, err
+}
+
+// CreateMovementRepository mencatat pergerakan stok, retur dicatat melalui ApplyReturn agar jumlah terjual
+// produk ikut berkurang
+func (r *inventoryRepository) CreateMovementRepository(movement *StockMovement) (err error) {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if movement.Type == TypeReturn {
+			return ApplyReturn(tx, movement)
+		}
+		return ApplyMovement(tx, movement)
+	})
+}
+
+// SumChangesSinceRepository menjumlahkan perubahan stok setiap produk sejak tanggal since (inklusif),
+// stok pada tanggal tersebut = stok saat ini - jumlah perubahan
+func (r *inventoryRepository) SumChangesSinceRepository(since time.Time) (result map[int]int, err error) {
+	var rows []struct {
+		ProductID int
+		Change    int
+	}
+	err = r.DB.Model(&StockMovement{}).
+		Select("product_id, SUM(CASE WHEN type = ? THEN -quantity ELSE quantity END) AS change", TypeOut).
+		Where("moved_at >= ?", since).
+		Group("product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result = make(map[int]int, len(rows))
+	for _, row := range rows {
+		result[row.ProductID] = row.Change
+	}
+	return result, nil
+}
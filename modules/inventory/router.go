@@ -0,0 +1,23 @@
+package inventory
+
+import (
+	"backend-profitrack/middleware"
+	"backend-profitrack/modules/product"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	repo := NewInventoryRepository(db)
+	productRepo := product.NewProductRepository(db)
+	service := NewInventoryService(repo, productRepo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.GET("/inventory/movements", service.GetAllMovementService)
+	api.GET("/inventory/products/:productID/movements", service.GetProductMovementService)
+	api.POST("/inventory/purchases", service.CreatePurchaseService)
+	api.POST("/inventory/returns", service.CreateReturnService)
+	api.POST("/inventory/adjustments", service.CreateAdjustmentService)
+}
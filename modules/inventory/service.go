@@ -0,0 +1,244 @@
+package inventory
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/product"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type Service interface {
+	GetAllMovementService(ctx *gin.Context)
+	GetProductMovementService(ctx *gin.Context)
+	CreatePurchaseService(ctx *gin.Context)
+	CreateReturnService(ctx *gin.Context)
+	CreateAdjustmentService(ctx *gin.Context)
+}
+
+type inventoryService struct {
+	repository        Repository
+	productRepository product.Repository
+}
+
+func NewInventoryService(repo Repository, productRepo product.Repository) Service {
+	return &inventoryService{
+		repository:        repo,
+		productRepository: productRepo,
+	}
+}
+
+// GetAllMovementService menampilkan jurnal stok, query opsional: product_id dan type (in, out, return, adjustment)
+func (service *inventoryService) GetAllMovementService(ctx *gin.Context) {
+	productID := 0
+	if query := ctx.Query("product_id"); query != "" {
+		var err error
+		productID, err = strconv.Atoi(query)
+		if err != nil {
+			response := map[string]string{"error": "produk ID tidak sesuai"}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	movementType := strings.ToLower(ctx.Query("type"))
+	if movementType != "" && movementType != TypeIn && movementType != TypeOut && movementType != TypeReturn && movementType != TypeAdjustment {
+		response := map[string]string{"error": "type harus in, out, return atau adjustment"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	movements, err := service.repository.GetAllMovementRepository(productID, movementType)
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data pergerakan stok"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(movements) == 0 {
+		response := map[string]string{"message": "data pergerakan stok masih kosong"}
+		helpers.ResponseJSON(ctx, http.StatusOK, response)
+		return
+	}
+
+	result := make([]ResponseMovement, 0, len(movements))
+	for _, movement := range movements {
+		result = append(result, toResponseMovement(movement))
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+// GetProductMovementService menampilkan riwayat stok satu produk beserta stok awal dan saldo saat ini
+func (service *inventoryService) GetProductMovementService(ctx *gin.Context) {
+	productID, err := strconv.Atoi(ctx.Param("productID"))
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	getProduct, err := service.productRepository.GetProductByIdRepository(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Produk dengan ID:%d tidak ditemukan", productID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	movements, err := service.repository.GetAllMovementRepository(productID, "")
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data pergerakan stok"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	result := ResponseProductMovements{
+		ProductID:    getProduct.ID,
+		ProductName:  getProduct.Name,
+		OpeningStock: getProduct.Stock,
+		Stock:        getProduct.Stock,
+		PurchaseCost: getProduct.PurchaseCost,
+		Movements:    make([]ResponseMovement, 0, len(movements)),
+	}
+	for _, movement := range movements {
+		result.OpeningStock -= movement.Change()
+		result.Movements = append(result.Movements, toResponseMovement(movement))
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+// CreatePurchaseService mencatat stok masuk dari pembelian dengan harga beli sebenarnya
+func (service *inventoryService) CreatePurchaseService(ctx *gin.Context) {
+	service.createMovement(ctx, TypeIn)
+}
+
+// CreateReturnService mencatat retur pelanggan atas sebuah penjualan: stok bertambah, jumlah terjual dan
+// pendapatan penjualan tersebut berkurang
+func (service *inventoryService) CreateReturnService(ctx *gin.Context) {
+	service.createMovement(ctx, TypeReturn)
+}
+
+// CreateAdjustmentService mencatat koreksi stok manual (mis. stok opname atau barang rusak), alasan wajib diisi
+func (service *inventoryService) CreateAdjustmentService(ctx *gin.Context) {
+	service.createMovement(ctx, TypeAdjustment)
+}
+
+func (service *inventoryService) createMovement(ctx *gin.Context, movementType string) {
+	var request RequestMovement
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := validateMovement(movementType, request); err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	movedAt := time.Now()
+	if request.MovedAt != "" {
+		var err error
+		movedAt, err = time.ParseInLocation(dateLayout, request.MovedAt, time.Local)
+		if err != nil {
+			response := map[string]string{"error": "format moved_at harus YYYY-MM-DD"}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	movement := StockMovement{
+		ProductID: request.ProductID,
+		SaleID:    request.SaleID,
+		Type:      movementType,
+		Quantity:  request.Quantity,
+		UnitCost:  request.UnitCost,
+		Reason:    strings.TrimSpace(request.Reason),
+		UserID:    ctx.GetInt("user_id"),
+		MovedAt:   movedAt,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err := service.repository.CreateMovementRepository(&movement)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Produk dengan ID:%d tidak ditemukan", request.ProductID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		if errors.Is(err, ErrSaleNotFound) {
+			response := map[string]string{"error": err.Error()}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		if errors.Is(err, ErrInsufficientStock) || errors.Is(err, ErrReturnProduct) || errors.Is(err, ErrReturnExceedsSales) {
+			response := map[string]string{"error": err.Error()}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+		response := map[string]string{"error": "gagal menyimpan pergerakan stok"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusCreated, toResponseMovement(movement))
+}
+
+func validateMovement(movementType string, request RequestMovement) error {
+	if movementType != TypeReturn && request.SaleID != nil {
+		return errors.New("sale_id hanya dapat diisi untuk retur")
+	}
+
+	switch movementType {
+	case TypeIn:
+		if request.Quantity <= 0 || request.UnitCost <= 0 {
+			return errors.New("quantity dan unit_cost harus lebih dari 0")
+		}
+	case TypeReturn:
+		if request.SaleID == nil {
+			return errors.New("sale_id harus diisi untuk retur")
+		}
+		if request.Quantity <= 0 {
+			return errors.New("quantity harus lebih dari 0")
+		}
+	case TypeAdjustment:
+		if request.Quantity == 0 {
+			return errors.New("quantity tidak boleh 0")
+		}
+		if strings.TrimSpace(request.Reason) == "" {
+			return errors.New("reason harus diisi untuk penyesuaian stok")
+		}
+	}
+	return nil
+}
+
+func toResponseMovement(movement StockMovement) ResponseMovement {
+	return ResponseMovement{
+		ID:          movement.ID,
+		ProductID:   movement.ProductID,
+		ProductName: movement.Product.Name,
+		Type:        movement.Type,
+		Quantity:    movement.Quantity,
+		Change:      movement.Change(),
+		UnitCost:    movement.UnitCost,
+		Balance:     movement.Balance,
+		Reason:      movement.Reason,
+		SaleID:      movement.SaleID,
+		MovedAt:     movement.MovedAt,
+	}
+}
@@ -0,0 +1,42 @@
+package job
+
+import "time"
+
+const (
+	TypeCalculation = "calculation"
+)
+
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Job adalah pekerjaan yang dijalankan di background, Progress dalam persen (0-100). Job yang berjalan dimiliki
+// satu server (Owner) yang memperbarui HeartbeatAt secara berkala, CancelRequested dibaca server tersebut
+// sehingga pembatalan dapat dikirim dari server mana pun
+type Job struct {
+	ID               int        `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	Type             string     `gorm:"varchar(25);not null" json:"type"`
+	MethodID         int        `gorm:"integer" json:"method_id"`
+	PeriodID         int        `gorm:"integer;not null;default:0" json:"period_id"`
+	UserID           int        `gorm:"integer" json:"user_id"`
+	Status           string     `gorm:"varchar(15);not null;index" json:"status"`
+	Progress         float64    `gorm:"double" json:"progress"`
+	Stage            string     `gorm:"varchar(100)" json:"stage"`
+	Error            string     `gorm:"type:text" json:"error"`
+	CalculationRunID int        `gorm:"integer" json:"calculation_run_id"`
+	Owner            string     `gorm:"varchar(100)" json:"owner"`
+	HeartbeatAt      *time.Time `json:"heartbeat_at"`
+	CancelRequested  bool       `gorm:"not null;default:false" json:"cancel_requested"`
+	StartedAt        *time.Time `json:"started_at"`
+	FinishedAt       *time.Time `json:"finished_at"`
+	CreatedAt        time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (j Job) Finished() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed || j.Status == StatusCancelled
+}
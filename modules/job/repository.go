@@ -0,0 +1,67 @@
+package job
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type Repository interface {
+	CreateJobRepository(job *Job) (err error)
+	GetAllJobRepository() (result []Job, err error)
+	GetJobByIdRepository(jobID int) (result Job, err error)
+	GetJobsByStatusRepository(statuses ...string) (result []Job, err error)
+	UpdateJobRepository(jobID int, values map[string]interface{}) (err error)
+	TransitionJobRepository(jobID int, from []string, values map[string]interface{}) (changed bool, err error)
+	ExpireJobRepository(jobID int, heartbeatBefore time.Time, values map[string]interface{}) (changed bool, err error)
+}
+
+type jobRepository struct {
+	DB *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) Repository {
+	return &jobRepository{
+		DB: db,
+	}
+}
+
+func (r *jobRepository) CreateJobRepository(job *Job) (err error) {
+	err = r.DB.Create(job).Error
+	return err
+}
+
+func (r *jobRepository) GetAllJobRepository() (result []Job, err error) {
+	err = r.DB.Order("id DESC").Find(&result).Error
+	return result, err
+}
+
+func (r *jobRepository) GetJobByIdRepository(jobID int) (result Job, err error) {
+	err = r.DB.First(&result, jobID).Error
+	return result, err
+}
+
+func (r *jobRepository) GetJobsByStatusRepository(statuses ...string) (result []Job, err error) {
+	err = r.DB.Where("status IN ?", statuses).Order("id ASC").Find(&result).Error
+	return result, err
+}
+
+func (r *jobRepository) UpdateJobRepository(jobID int, values map[string]interface{}) (err error) {
+	err = r.DB.Model(&Job{}).Where("id = ?", jobID).Updates(values).Error
+	return err
+}
+
+// TransitionJobRepository hanya mengubah job yang statusnya masih salah satu dari from,
+// sehingga pembatalan dan worker tidak saling menimpa status
+func (r *jobRepository) TransitionJobRepository(jobID int, from []string, values map[string]interface{}) (changed bool, err error) {
+	result := r.DB.Model(&Job{}).Where("id = ? AND status IN ?", jobID, from).Updates(values)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ExpireJobRepository mengubah job yang masih berjalan tetapi heartbeat terakhirnya sebelum heartbeatBefore
+// (atau belum pernah ada), job yang masih diperbarui pemiliknya tidak ikut berubah
+func (r *jobRepository) ExpireJobRepository(jobID int, heartbeatBefore time.Time, values map[string]interface{}) (changed bool, err error) {
+	result := r.DB.Model(&Job{}).
+		Where("id = ? AND status = ? AND (heartbeat_at IS NULL OR heartbeat_at < ?)", jobID, StatusRunning, heartbeatBefore).
+		Updates(values)
+	return result.RowsAffected > 0, result.Error
+}
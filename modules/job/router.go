@@ -0,0 +1,52 @@
+package job
+
+import (
+	"backend-profitrack/config"
+	"backend-profitrack/middleware"
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/criteria_score"
+	"backend-profitrack/modules/final_score"
+	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
+	"backend-profitrack/modules/product"
+	"backend-profitrack/modules/score"
+	"context"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	repo := NewJobRepository(db)
+	methodRepo := method.NewMethodRepository(db)
+	periodRepo := period.NewPeriodRepository(db)
+	scoreService := score.NewScoreService(
+		score.NewScoreRepository(db),
+		product.NewProductRepository(db),
+		criteria.NewCriteriaRepository(db),
+		methodRepo,
+		criteria_score.NewCriteriaScoreRepository(db),
+		final_score.NewFinalScoreRepository(db),
+		periodRepo,
+	)
+
+	calculate := func(ctx context.Context, methodID int, periodID int, userID int, progress score.ProgressFunc) (int, error) {
+		calculation, err := scoreService.RunCalculation(ctx, methodID, periodID, userID, progress)
+		if err != nil {
+			return 0, err
+		}
+		return calculation.Run.ID, nil
+	}
+
+	runner := NewRunner(repo, calculate, config.JobWorkers(), config.InstanceID())
+	runner.Recover()
+	service := NewJobService(repo, methodRepo, periodRepo, runner)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.GET("/jobs", service.GetAllJobService)
+	api.GET("/jobs/:id", service.GetJobByIdService)
+	api.GET("/jobs/:id/events", service.StreamJobEventsService)
+	api.POST("/jobs/calculations/:methodID", service.SubmitCalculationService)
+	api.POST("/jobs/:id/cancel", service.CancelJobService)
+}
@@ -0,0 +1,248 @@
+package job
+
+import (
+	"backend-profitrack/modules/score"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// CalculateFunc menjalankan perhitungan sebuah metode pada periode periodID (0 untuk data produk saat ini)
+// dan mengembalikan ID riwayat perhitungannya
+type CalculateFunc func(ctx context.Context, methodID int, periodID int, userID int, progress score.ProgressFunc) (runID int, err error)
+
+const (
+	// heartbeatInterval adalah jeda pemilik job memperbarui heartbeat_at dan membaca cancel_requested
+	heartbeatInterval = 10 * time.Second
+	// leaseTimeout adalah batas heartbeat terakhir sebelum job yang berjalan dianggap ditinggalkan pemiliknya
+	leaseTimeout = 3 * heartbeatInterval
+)
+
+// Runner adalah worker pool di dalam proses, antrean disimpan di memori sedangkan status disimpan di tabel jobs.
+// Setiap server memiliki instanceID sendiri sehingga job yang berjalan di server lain tidak ikut dipulihkan
+type Runner struct {
+	repository Repository
+	calculate  CalculateFunc
+	instanceID string
+	queue      chan int
+	mu         sync.Mutex
+	cancels    map[int]context.CancelFunc
+}
+
+func NewRunner(repo Repository, calculate CalculateFunc, workers int, instanceID string) *Runner {
+	runner := &Runner{
+		repository: repo,
+		calculate:  calculate,
+		instanceID: instanceID,
+		queue:      make(chan int, 100),
+		cancels:    make(map[int]context.CancelFunc),
+	}
+
+	for i := 0; i < workers; i++ {
+		go runner.work()
+	}
+	return runner
+}
+
+// Enqueue tidak menunggu worker kosong, job tetap berstatus queued sampai diambil worker
+func (r *Runner) Enqueue(jobID int) {
+	go func() {
+		r.queue <- jobID
+	}()
+}
+
+// Cancel menghentikan job yang sedang berjalan di proses ini, job di server lain dihentikan lewat cancel_requested
+func (r *Runner) Cancel(jobID int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cancel, exists := r.cancels[jobID]
+	if exists {
+		cancel()
+	}
+	return exists
+}
+
+// Recover dipanggil saat server dimulai: job yang masih antre dimasukkan kembali ke antrean, job berjalan milik
+// server ini (instanceID sama) ditandai gagal, lalu job yang heartbeat-nya melewati leaseTimeout diperiksa
+// secara berkala karena pemiliknya (server mana pun) dianggap sudah berhenti
+func (r *Runner) Recover() {
+	jobs, err := r.repository.GetJobsByStatusRepository(StatusQueued, StatusRunning)
+	if err != nil {
+		log.Printf("gagal memuat job yang belum selesai: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if job.Status == StatusQueued {
+			r.Enqueue(job.ID)
+			continue
+		}
+
+		if job.Owner != r.instanceID {
+			continue
+		}
+		_, err = r.repository.TransitionJobRepository(job.ID, []string{StatusRunning}, map[string]interface{}{
+			"status":      StatusFailed,
+			"error":       "server dimulai ulang saat job berjalan",
+			"finished_at": time.Now(),
+		})
+		if err != nil {
+			log.Printf("gagal memperbarui job %d: %v", job.ID, err)
+		}
+	}
+
+	r.expire()
+	go func() {
+		ticker := time.NewTicker(leaseTimeout)
+		defer ticker.Stop()
+		for range ticker.C {
+			r.expire()
+		}
+	}()
+}
+
+// expire menandai gagal job berjalan yang heartbeat-nya lebih lama dari leaseTimeout
+func (r *Runner) expire() {
+	jobs, err := r.repository.GetJobsByStatusRepository(StatusRunning)
+	if err != nil {
+		log.Printf("gagal memuat job yang sedang berjalan: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		_, err = r.repository.ExpireJobRepository(job.ID, now.Add(-leaseTimeout), map[string]interface{}{
+			"status":      StatusFailed,
+			"error":       fmt.Sprintf("server %s berhenti mengirim heartbeat saat job berjalan", job.Owner),
+			"finished_at": now,
+		})
+		if err != nil {
+			log.Printf("gagal memperbarui job %d: %v", job.ID, err)
+		}
+	}
+}
+
+func (r *Runner) work() {
+	for jobID := range r.queue {
+		r.run(jobID)
+	}
+}
+
+func (r *Runner) run(jobID int) {
+	job, err := r.repository.GetJobByIdRepository(jobID)
+	if err != nil {
+		log.Printf("gagal memuat job %d: %v", jobID, err)
+		return
+	}
+
+	// job yang sudah dibatalkan selama antre tidak dijalankan
+	startedAt := time.Now()
+	started, err := r.repository.TransitionJobRepository(jobID, []string{StatusQueued}, map[string]interface{}{
+		"status":       StatusRunning,
+		"owner":        r.instanceID,
+		"started_at":   startedAt,
+		"heartbeat_at": startedAt,
+	})
+	if err != nil || !started {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	r.cancels[jobID] = cancel
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.cancels, jobID)
+		r.mu.Unlock()
+		cancel()
+	}()
+	go r.heartbeat(ctx, jobID, cancel)
+
+	// progres hanya ditulis ke database jika naik minimal 1% atau tahapnya berganti
+	lastProgress, lastStage := -1.0, ""
+	progress := func(current score.Progress) {
+		if current.Percent-lastProgress < 1 && current.Stage == lastStage {
+			return
+		}
+		lastProgress, lastStage = current.Percent, current.Stage
+
+		_, err := r.repository.TransitionJobRepository(jobID, []string{StatusRunning}, map[string]interface{}{
+			"progress": math.Round(current.Percent*100) / 100,
+			"stage":    current.Stage,
+		})
+		if err != nil {
+			log.Printf("gagal memperbarui progres job %d: %v", jobID, err)
+		}
+	}
+
+	runID, err := r.calculate(ctx, job.MethodID, job.PeriodID, job.UserID, progress)
+
+	values := map[string]interface{}{
+		"finished_at": time.Now(),
+	}
+	switch {
+	case err == nil:
+		values["status"] = StatusSucceeded
+		values["progress"] = 100
+		values["calculation_run_id"] = runID
+	case errors.Is(err, context.Canceled):
+		values["status"] = StatusCancelled
+	default:
+		values["status"] = StatusFailed
+		values["error"] = err.Error()
+	}
+
+	// job yang sudah ditandai gagal karena lease habis tidak ditimpa hasil worker ini
+	finished, err := r.repository.TransitionJobRepository(jobID, []string{StatusRunning}, values)
+	if err != nil {
+		log.Printf("gagal memperbarui status job %d: %v", jobID, err)
+		return
+	}
+	if !finished {
+		log.Printf("job %d sudah tidak berjalan, status %s tidak disimpan", jobID, values["status"])
+	}
+}
+
+// heartbeat memperbarui heartbeat_at selama job berjalan dan menghentikan perhitungan jika pembatalan diminta
+// dari server lain atau job sudah tidak berstatus running (misalnya ditandai gagal karena lease habis)
+func (r *Runner) heartbeat(ctx context.Context, jobID int, cancel context.CancelFunc) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		alive, err := r.repository.TransitionJobRepository(jobID, []string{StatusRunning}, map[string]interface{}{
+			"heartbeat_at": time.Now(),
+		})
+		if err != nil {
+			log.Printf("gagal memperbarui heartbeat job %d: %v", jobID, err)
+			continue
+		}
+		if !alive {
+			cancel()
+			return
+		}
+
+		job, err := r.repository.GetJobByIdRepository(jobID)
+		if err != nil {
+			log.Printf("gagal memuat job %d: %v", jobID, err)
+			continue
+		}
+		if job.CancelRequested {
+			cancel()
+			return
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package job
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Service interface {
+	SubmitCalculationService(ctx *gin.Context)
+	GetAllJobService(ctx *gin.Context)
+	GetJobByIdService(ctx *gin.Context)
+	CancelJobService(ctx *gin.Context)
+	StreamJobEventsService(ctx *gin.Context)
+}
+
+// eventPollInterval adalah jeda pembacaan status job saat streaming progres
+const eventPollInterval = 500 * time.Millisecond
+
+type jobService struct {
+	repository       Repository
+	methodRepository method.Repository
+	periodRepository period.Repository
+	runner           *Runner
+}
+
+func NewJobService(repo Repository, methodRepo method.Repository, periodRepo period.Repository, runner *Runner) Service {
+	return &jobService{
+		repository:       repo,
+		methodRepository: methodRepo,
+		periodRepository: periodRepo,
+		runner:           runner,
+	}
+}
+
+// SubmitCalculationService memasukkan perhitungan metode ke antrean dan langsung mengembalikan job-nya,
+// status dan progres dipantau lewat GET /jobs/:id. Query period_id sama dengan endpoint perhitungan
+func (service *jobService) SubmitCalculationService(ctx *gin.Context) {
+	methodID, err := strconv.Atoi(ctx.Param("methodID"))
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	getPeriod, statusCode, err := period.Resolve(service.periodRepository, ctx.Query("period_id"))
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	_, err = service.methodRepository.GetMethodByIdRepository(methodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Metode dengan ID:%d tidak ditemukan", methodID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	newJob := Job{
+		Type:      TypeCalculation,
+		MethodID:  methodID,
+		PeriodID:  getPeriod.ID,
+		UserID:    ctx.GetInt("user_id"),
+		Status:    StatusQueued,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err = service.repository.CreateJobRepository(&newJob)
+	if err != nil {
+		response := map[string]string{"error": "gagal membuat job"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	service.runner.Enqueue(newJob.ID)
+	helpers.ResponseJSON(ctx, http.StatusAccepted, newJob)
+}
+
+func (service *jobService) GetAllJobService(ctx *gin.Context) {
+	jobs, err := service.repository.GetAllJobRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data job"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(jobs) == 0 {
+		response := map[string]string{"message": "data job masih kosong"}
+		helpers.ResponseJSON(ctx, http.StatusOK, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, jobs)
+}
+
+func (service *jobService) GetJobByIdService(ctx *gin.Context) {
+	getJob, statusCode, err := service.findJob(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, getJob)
+}
+
+// CancelJobService membatalkan job yang masih antre atau sedang berjalan, perhitungan yang dibatalkan
+// di tengah jalan di-rollback sehingga nilai akhir sebelumnya tetap utuh
+func (service *jobService) CancelJobService(ctx *gin.Context) {
+	getJob, statusCode, err := service.findJob(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	if getJob.Finished() {
+		response := map[string]string{"error": fmt.Sprintf("job sudah selesai dengan status %s", getJob.Status)}
+		helpers.ResponseJSON(ctx, http.StatusConflict, response)
+		return
+	}
+
+	cancelled, err := service.repository.TransitionJobRepository(getJob.ID, []string{StatusQueued}, map[string]interface{}{
+		"status":      StatusCancelled,
+		"finished_at": time.Now(),
+	})
+	if err != nil {
+		response := map[string]string{"error": "gagal membatalkan job"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	// job sudah diambil worker, status diperbarui oleh worker setelah perhitungan berhenti. Job yang berjalan
+	// di server lain dihentikan pemiliknya saat heartbeat berikutnya membaca cancel_requested
+	if !cancelled && !service.runner.Cancel(getJob.ID) {
+		requested, err := service.repository.TransitionJobRepository(getJob.ID, []string{StatusRunning}, map[string]interface{}{
+			"cancel_requested": true,
+		})
+		if err != nil {
+			response := map[string]string{"error": "gagal membatalkan job"}
+			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+			return
+		}
+		if !requested {
+			response := map[string]string{"error": "job sudah selesai"}
+			helpers.ResponseJSON(ctx, http.StatusConflict, response)
+			return
+		}
+	}
+
+	response := map[string]string{"message": fmt.Sprintf("Pembatalan job ID:%d diproses", getJob.ID)}
+	helpers.ResponseJSON(ctx, http.StatusAccepted, response)
+}
+
+// StreamJobEventsService mengirim progres job sebagai Server-Sent Events: "progress" setiap progres atau tahap
+// berubah, lalu "result" (job berhasil) atau "error" (gagal/dibatalkan) sebagai event terakhir.
+// Status dibaca dari tabel jobs sehingga dapat dipantau dari instance server mana pun
+func (service *jobService) StreamJobEventsService(ctx *gin.Context) {
+	getJob, statusCode, err := service.findJob(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	requestCtx := ctx.Request.Context()
+	events := make(chan helpers.Event, 16)
+	send := helpers.EventSender(requestCtx, events)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(eventPollInterval)
+		defer ticker.Stop()
+
+		lastProgress, lastStage, lastStatus := -1.0, "", ""
+		for {
+			if getJob.Progress != lastProgress || getJob.Stage != lastStage || getJob.Status != lastStatus {
+				lastProgress, lastStage, lastStatus = getJob.Progress, getJob.Stage, getJob.Status
+				send("progress", gin.H{"status": getJob.Status, "percent": getJob.Progress, "stage": getJob.Stage})
+			}
+
+			switch getJob.Status {
+			case StatusSucceeded:
+				send("result", getJob)
+				return
+			case StatusFailed, StatusCancelled:
+				send("error", gin.H{"status": getJob.Status, "error": getJob.Error})
+				return
+			}
+
+			select {
+			case <-requestCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			getJob, err = service.repository.GetJobByIdRepository(getJob.ID)
+			if err != nil {
+				send("error", gin.H{"error": "gagal mengambil data job"})
+				return
+			}
+		}
+	}()
+
+	helpers.StreamEvents(ctx, events)
+}
+
+func (service *jobService) findJob(ctx *gin.Context) (Job, int, error) {
+	jobID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return Job{}, http.StatusBadRequest, errors.New("ID tidak sesuai")
+	}
+
+	getJob, err := service.repository.GetJobByIdRepository(jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Job{}, http.StatusNotFound, fmt.Errorf("Job dengan ID:%d tidak ditemukan", jobID)
+		}
+		return Job{}, http.StatusInternalServerError, err
+	}
+	return getJob, http.StatusOK, nil
+}
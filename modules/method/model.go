@@ -5,11 +5,14 @@ import "time"
 type Method struct {
 	ID        int       `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
 	Name      string    `gorm:"varchar(25)" json:"name"`
+	Algorithm string    `gorm:"varchar(25)" json:"algorithm"`
 	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
 type ResponseMethod struct {
-	ID   int    ` json:"id"`
-	Name string `json:"name"`
+	ID         int               ` json:"id"`
+	Name       string            `json:"name"`
+	Algorithm  string            `json:"algorithm"`
+	Parameters map[string]string `json:"parameters"`
 }
@@ -22,19 +22,38 @@ func NewMethodRepository(db *gorm.DB) Repository {
 		return nil
 	}
 
-	methodMap := make(map[string]bool)
+	methodMap := make(map[string]Method)
 	for _, method := range methods {
-		methodMap[method.Name] = true
+		methodMap[method.Name] = method
 	}
 
-	requiredMethods := []string{"SMART", "MOORA"}
+	// nama metode -> algoritma perhitungan pada package ranking
+	requiredMethods := []Method{
+		{Name: "SMART", Algorithm: "SMART"},
+		{Name: "MOORA", Algorithm: "MOORA"},
+		{Name: "TOPSIS", Algorithm: "TOPSIS"},
+		// metode konsensus menggabungkan skor akhir metode lain
+		{Name: "BORDA", Algorithm: "BORDA"},
+		{Name: "COPELAND", Algorithm: "COPELAND"},
+		{Name: "AVERAGE-RANK", Algorithm: "AVERAGE-RANK"},
+	}
 
-	for _, methodName := range requiredMethods {
-		if !methodMap[methodName] {
-			newMethod := Method{Name: methodName}
+	for _, requiredMethod := range requiredMethods {
+		existingMethod, exists := methodMap[requiredMethod.Name]
+		if !exists {
+			newMethod := requiredMethod
 			if err = db.Create(&newMethod).Error; err != nil {
 				return nil
 			}
+			continue
+		}
+
+		// data metode lama belum memiliki algoritma
+		if existingMethod.Algorithm == "" {
+			existingMethod.Algorithm = requiredMethod.Algorithm
+			if err = db.Save(&existingMethod).Error; err != nil {
+				return nil
+			}
 		}
 	}
 
@@ -42,7 +61,7 @@ func NewMethodRepository(db *gorm.DB) Repository {
 }
 
 func (r *methodRepository) GetAllMethodRepository() (result []Method, err error) {
-	err = r.DB.Find(&result).Error
+	err = r.DB.Order("id ASC").Find(&result).Error
 	return
 }
 
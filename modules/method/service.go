@@ -2,6 +2,7 @@ package method
 
 import (
 	"backend-profitrack/helpers"
+	"backend-profitrack/modules/ranking"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
@@ -35,10 +36,20 @@ func (service *methodService) GetAllMethodService(ctx *gin.Context) {
 
 	var result []ResponseMethod
 	for _, method := range methods {
-		result = append(result, ResponseMethod{
-			ID:   method.ID,
-			Name: method.Name,
-		})
+		response := ResponseMethod{
+			ID:        method.ID,
+			Name:      method.Name,
+			Algorithm: method.Algorithm,
+		}
+
+		// metode yang belum terhubung dengan algoritma tetap ditampilkan tanpa parameter
+		if algorithm, ok := ranking.Get(method.Algorithm); ok {
+			response.Parameters = algorithm.Parameters()
+		} else if consensus, ok := ranking.GetConsensus(method.Algorithm); ok {
+			response.Parameters = consensus.Parameters()
+		}
+
+		result = append(result, response)
 	}
 
 	if result == nil {
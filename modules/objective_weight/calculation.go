@@ -0,0 +1,154 @@
+package objective_weight
+
+import (
+	"backend-profitrack/modules/ranking"
+	"math"
+)
+
+// EntropyResult adalah hasil metode Shannon Entropy
+type EntropyResult struct {
+	Weights    []float64
+	Entropy    []float64
+	Divergence []float64
+}
+
+// CriticResult adalah hasil metode CRITIC (CRiteria Importance Through Intercriteria Correlation)
+type CriticResult struct {
+	Weights            []float64
+	StandardDeviations []float64
+	Correlation        [][]float64
+	Information        []float64
+}
+
+// Entropy: pij = xij / Σxij, Ej = -1/ln(m) Σ pij ln pij, dj = 1 - Ej, wj = dj / Σdj
+func Entropy(matrix ranking.Matrix) EntropyResult {
+	rows, columns := len(matrix.Values), len(matrix.Criteria)
+	result := EntropyResult{
+		Entropy:    make([]float64, columns),
+		Divergence: make([]float64, columns),
+	}
+
+	k := 1 / math.Log(float64(rows))
+	for j := 0; j < columns; j++ {
+		column := shiftNonNegative(matrix, j)
+
+		total := 0.0
+		for _, value := range column {
+			total += value
+		}
+
+		entropy := 0.0
+		if total > 0 {
+			for _, value := range column {
+				if p := value / total; p > 0 {
+					entropy -= p * math.Log(p)
+				}
+			}
+			entropy *= k
+		} else {
+			// kolom bernilai nol seluruhnya tidak membawa informasi
+			entropy = 1
+		}
+
+		result.Entropy[j] = entropy
+		result.Divergence[j] = 1 - entropy
+	}
+
+	result.Weights = normalizeWeights(result.Divergence)
+	return result
+}
+
+// Critic: normalisasi min-max sesuai tipe kriteria, Cj = σj Σk (1 - rjk), wj = Cj / ΣCj
+func Critic(matrix ranking.Matrix) CriticResult {
+	rows, columns := len(matrix.Values), len(matrix.Criteria)
+	normalized := ranking.SMART{}.Normalize(matrix)
+
+	means := make([]float64, columns)
+	for _, row := range normalized {
+		for j, value := range row {
+			means[j] += value / float64(rows)
+		}
+	}
+
+	result := CriticResult{
+		StandardDeviations: make([]float64, columns),
+		Correlation:        make([][]float64, columns),
+		Information:        make([]float64, columns),
+	}
+
+	for j := 0; j < columns; j++ {
+		variance := 0.0
+		for _, row := range normalized {
+			variance += math.Pow(row[j]-means[j], 2)
+		}
+		result.StandardDeviations[j] = math.Sqrt(variance / float64(rows-1))
+	}
+
+	for j := 0; j < columns; j++ {
+		result.Correlation[j] = make([]float64, columns)
+		for k := 0; k < columns; k++ {
+			if j == k {
+				result.Correlation[j][k] = 1
+				continue
+			}
+
+			// korelasi dengan kolom konstan tidak terdefinisi, dianggap 0
+			if result.StandardDeviations[j] == 0 || result.StandardDeviations[k] == 0 {
+				continue
+			}
+
+			covariance := 0.0
+			for _, row := range normalized {
+				covariance += (row[j] - means[j]) * (row[k] - means[k])
+			}
+			covariance /= float64(rows - 1)
+			result.Correlation[j][k] = covariance / (result.StandardDeviations[j] * result.StandardDeviations[k])
+		}
+	}
+
+	for j := 0; j < columns; j++ {
+		conflict := 0.0
+		for k := 0; k < columns; k++ {
+			conflict += 1 - result.Correlation[j][k]
+		}
+		result.Information[j] = result.StandardDeviations[j] * conflict
+	}
+
+	result.Weights = normalizeWeights(result.Information)
+	return result
+}
+
+// shiftNonNegative menggeser kolom yang memiliki nilai negatif agar nilai terkecilnya 0
+func shiftNonNegative(matrix ranking.Matrix, column int) []float64 {
+	values := make([]float64, len(matrix.Values))
+	minValue := 0.0
+	for i, row := range matrix.Values {
+		values[i] = row[column]
+		minValue = math.Min(minValue, row[column])
+	}
+
+	if minValue < 0 {
+		for i := range values {
+			values[i] -= minValue
+		}
+	}
+	return values
+}
+
+// normalizeWeights membagi setiap nilai dengan totalnya, jika total 0 bobot dibagi rata
+func normalizeWeights(values []float64) []float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+
+	weights := make([]float64, len(values))
+	for i, value := range values {
+		if total > 0 {
+			weights[i] = value / total
+		} else {
+			weights[i] = 1 / float64(len(values))
+		}
+	}
+	return weights
+}
@@ -0,0 +1,107 @@
+package objective_weight
+
+import (
+	"backend-profitrack/modules/ranking"
+	"math"
+	"testing"
+)
+
+func testMatrix(types []string, values [][]float64) ranking.Matrix {
+	matrix := ranking.Matrix{ProductIDs: make([]int, len(values)), Values: values}
+	for j, criterionType := range types {
+		matrix.Criteria = append(matrix.Criteria, ranking.Criterion{ID: j + 1, Type: criterionType})
+	}
+	return matrix
+}
+
+func TestEntropy(t *testing.T) {
+	tests := []struct {
+		name        string
+		matrix      ranking.Matrix
+		wantEntropy []float64
+		wantWeights []float64
+	}{
+		{
+			// kolom ketiga digeser menjadi (0, 2)
+			name:        "kolom seragam, terpusat dan negatif",
+			matrix:      testMatrix([]string{"Benefit", "Benefit", "Benefit"}, [][]float64{{1, 1, -1}, {1, 0, 1}}),
+			wantEntropy: []float64{1, 0, 0},
+			wantWeights: []float64{0, 0.5, 0.5},
+		},
+		{
+			// p = (0.25, 0.25, 0.5): E = 1.5 ln 2 / ln 3
+			name:        "tiga produk",
+			matrix:      testMatrix([]string{"Benefit", "Benefit"}, [][]float64{{1, 1}, {1, 1}, {2, 1}}),
+			wantEntropy: []float64{1.5 * math.Log(2) / math.Log(3), 1},
+			wantWeights: []float64{1, 0},
+		},
+		{
+			name:        "semua kolom nol dibagi rata",
+			matrix:      testMatrix([]string{"Benefit", "Cost"}, [][]float64{{0, 0}, {0, 0}}),
+			wantEntropy: []float64{1, 1},
+			wantWeights: []float64{0.5, 0.5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Entropy(test.matrix)
+			for j := range test.wantWeights {
+				if math.Abs(result.Entropy[j]-test.wantEntropy[j]) > 1e-9 {
+					t.Errorf("entropi kolom %d = %v, want %v", j, result.Entropy[j], test.wantEntropy[j])
+				}
+				if math.Abs(result.Weights[j]-test.wantWeights[j]) > 1e-9 {
+					t.Errorf("bobot kolom %d = %v, want %v", j, result.Weights[j], test.wantWeights[j])
+				}
+			}
+		})
+	}
+}
+
+func TestCritic(t *testing.T) {
+	// kolom pertama ternormalisasi (0, 0.5, 1) dengan σ = 0.5, kolom kedua (0, 0, 1) atau (1, 1, 0)
+	// dengan σ = sqrt(1/3) dan |r| = sqrt(3)/2, sehingga wj sebanding dengan σj
+	values := [][]float64{{0, 0}, {1, 0}, {2, 1}}
+	deviation := math.Sqrt(1.0 / 3)
+
+	tests := []struct {
+		name            string
+		matrix          ranking.Matrix
+		wantCorrelation float64
+		wantWeights     []float64
+	}{
+		{
+			name:            "dua kriteria benefit",
+			matrix:          testMatrix([]string{"Benefit", "Benefit"}, values),
+			wantCorrelation: math.Sqrt(3) / 2,
+			wantWeights:     []float64{0.5 / (0.5 + deviation), deviation / (0.5 + deviation)},
+		},
+		{
+			name:            "kriteria cost dibalik",
+			matrix:          testMatrix([]string{"Benefit", "Cost"}, values),
+			wantCorrelation: -math.Sqrt(3) / 2,
+			wantWeights:     []float64{0.5 / (0.5 + deviation), deviation / (0.5 + deviation)},
+		},
+		{
+			// (0,1,2) dan (2,1,0) berkorelasi -1: Cj = 0.5 * 2, kolom konstan tidak membawa informasi
+			name:            "kolom konstan",
+			matrix:          testMatrix([]string{"Benefit", "Benefit", "Benefit"}, [][]float64{{0, 2, 5}, {1, 1, 5}, {2, 0, 5}}),
+			wantCorrelation: -1,
+			wantWeights:     []float64{0.5, 0.5, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Critic(test.matrix)
+			if math.Abs(result.Correlation[0][1]-test.wantCorrelation) > 1e-9 {
+				t.Errorf("korelasi = %v, want %v", result.Correlation[0][1], test.wantCorrelation)
+			}
+			for j, want := range test.wantWeights {
+				if math.Abs(result.Weights[j]-want) > 1e-9 {
+					t.Errorf("bobot kolom %d = %v, want %v", j, result.Weights[j], want)
+				}
+			}
+		})
+	}
+}
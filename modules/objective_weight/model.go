@@ -0,0 +1,29 @@
+package objective_weight
+
+// Metode pembobotan objektif yang didukung
+const (
+	MethodEntropy = "entropy"
+	MethodCRITIC  = "critic"
+)
+
+type ResponseCriterionWeight struct {
+	ID             int     `json:"id"`
+	Name           string  `json:"name"`
+	Type           string  `json:"type"`
+	CurrentWeight  float64 `json:"current_weight"`
+	ProposedWeight float64 `json:"proposed_weight"`
+}
+
+// ResponseObjectiveWeight berisi bobot usulan beserta nilai antara sesuai metode yang dipakai
+type ResponseObjectiveWeight struct {
+	Method             string                    `json:"method"`
+	PeriodID           int                       `json:"period_id"`
+	ProductCount       int                       `json:"product_count"`
+	Criteria           []ResponseCriterionWeight `json:"criteria"`
+	Entropy            []float64                 `json:"entropy,omitempty"`
+	Divergence         []float64                 `json:"divergence,omitempty"`
+	StandardDeviations []float64                 `json:"standard_deviations,omitempty"`
+	Correlation        [][]float64               `json:"correlation,omitempty"`
+	Information        []float64                 `json:"information,omitempty"`
+	Persisted          bool                      `json:"persisted"`
+}
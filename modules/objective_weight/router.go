@@ -0,0 +1,23 @@
+package objective_weight
+
+import (
+	"backend-profitrack/middleware"
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/criteria_score"
+	"backend-profitrack/modules/period"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	criteriaRepo := criteria.NewCriteriaRepository(db)
+	criteriaScoreRepo := criteria_score.NewCriteriaScoreRepository(db)
+	periodRepo := period.NewPeriodRepository(db)
+	service := NewObjectiveWeightService(criteriaRepo, criteriaScoreRepo, periodRepo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.GET("/objective_weights/:method", service.ProposeWeightService)
+	api.POST("/objective_weights/:method", service.ApplyWeightService)
+}
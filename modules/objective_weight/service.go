@@ -0,0 +1,139 @@
+package objective_weight
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/criteria_score"
+	"backend-profitrack/modules/period"
+	"backend-profitrack/modules/ranking"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Service interface {
+	ProposeWeightService(ctx *gin.Context)
+	ApplyWeightService(ctx *gin.Context)
+}
+
+type objectiveWeightService struct {
+	criteriaRepository      criteria.Repository
+	criteriaScoreRepository criteria_score.Repository
+	periodRepository        period.Repository
+}
+
+func NewObjectiveWeightService(criteriaRepo criteria.Repository, criteriaScoreRepo criteria_score.Repository, periodRepo period.Repository) Service {
+	return &objectiveWeightService{
+		criteriaRepository:      criteriaRepo,
+		criteriaScoreRepository: criteriaScoreRepo,
+		periodRepository:        periodRepo,
+	}
+}
+
+// ProposeWeightService mengembalikan bobot usulan tanpa mengubah data kriteria, query period_id memakai
+// nilai kriteria periode tersebut
+func (service *objectiveWeightService) ProposeWeightService(ctx *gin.Context) {
+	result, _, statusCode, err := service.calculate(strings.ToLower(ctx.Param("method")), ctx.Query("period_id"))
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+// ApplyWeightService menghitung bobot lalu menyimpannya sebagai bobot kriteria
+func (service *objectiveWeightService) ApplyWeightService(ctx *gin.Context) {
+	result, criteriaList, statusCode, err := service.calculate(strings.ToLower(ctx.Param("method")), ctx.Query("period_id"))
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	for i, item := range result.Criteria {
+		// bobot 0 berarti nilai kriteria sama untuk semua produk dan tidak dapat disimpan
+		if item.ProposedWeight <= 0 {
+			response := map[string]string{"error": fmt.Sprintf("bobot kriteria %s bernilai 0 karena nilainya tidak membedakan produk", item.Name)}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+		criteriaList[i].Weight = item.ProposedWeight
+		criteriaList[i].UpdatedAt = time.Now()
+	}
+
+	err = service.criteriaRepository.SaveAllCriteriaRepository(criteriaList)
+	if err != nil {
+		response := map[string]string{"error": "gagal menyimpan bobot kriteria"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	result.Persisted = true
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+func (service *objectiveWeightService) calculate(method string, periodID string) (result ResponseObjectiveWeight, criteriaList []criteria.Criteria, statusCode int, err error) {
+	if method != MethodEntropy && method != MethodCRITIC {
+		return result, nil, http.StatusBadRequest, fmt.Errorf("metode pembobotan harus %s atau %s", MethodEntropy, MethodCRITIC)
+	}
+
+	getPeriod, statusCode, err := period.Resolve(service.periodRepository, periodID)
+	if err != nil {
+		return result, nil, statusCode, err
+	}
+
+	criteriaList, err = service.criteriaRepository.GetAllCriteriaRepository()
+	if err != nil {
+		return result, nil, http.StatusInternalServerError, fmt.Errorf("gagal mengambil data kriteria")
+	}
+
+	scores, err := service.criteriaScoreRepository.GetAllCriteriaScoreRepository(getPeriod.ID)
+	if err != nil {
+		return result, nil, http.StatusInternalServerError, fmt.Errorf("gagal mengambil data nilai kriteria")
+	}
+
+	matrix, err := ranking.BuildMatrix(criteriaList, scores)
+	if err != nil {
+		return result, nil, http.StatusBadRequest, err
+	}
+
+	if len(matrix.ProductIDs) < 2 {
+		return result, nil, http.StatusBadRequest, fmt.Errorf("pembobotan objektif membutuhkan minimal 2 produk")
+	}
+
+	result = ResponseObjectiveWeight{
+		Method:       method,
+		PeriodID:     getPeriod.ID,
+		ProductCount: len(matrix.ProductIDs),
+	}
+
+	var weights []float64
+	if method == MethodEntropy {
+		entropy := Entropy(matrix)
+		weights = entropy.Weights
+		result.Entropy = entropy.Entropy
+		result.Divergence = entropy.Divergence
+	} else {
+		critic := Critic(matrix)
+		weights = critic.Weights
+		result.StandardDeviations = critic.StandardDeviations
+		result.Correlation = critic.Correlation
+		result.Information = critic.Information
+	}
+
+	for i, item := range criteriaList {
+		result.Criteria = append(result.Criteria, ResponseCriterionWeight{
+			ID:             item.ID,
+			Name:           item.Name,
+			Type:           item.Type,
+			CurrentWeight:  item.Weight,
+			ProposedWeight: weights[i],
+		})
+	}
+
+	return result, criteriaList, http.StatusOK, nil
+}
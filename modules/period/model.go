@@ -0,0 +1,112 @@
+package period
+
+import (
+	"backend-profitrack/modules/product"
+	"backend-profitrack/modules/sales"
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Period adalah periode pelaporan (mis. bulan atau kuartal), StartDate dan EndDate inklusif
+type Period struct {
+	ID        int       `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	Name      string    `gorm:"varchar(50);UNIQUE;not null" json:"name"`
+	StartDate time.Time `gorm:"type:date;not null" json:"start_date"`
+	EndDate   time.Time `gorm:"type:date;not null" json:"end_date"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// Range mengembalikan rentang tanggal periode dalam bentuk yang dipakai modul sales (To eksklusif)
+func (p Period) Range() sales.Period {
+	return sales.Period{
+		From: p.StartDate,
+		To:   p.EndDate.AddDate(0, 0, 1),
+	}
+}
+
+// Label dipakai pada judul laporan, mis. "Q3 2024 (01-07-2024 s/d 30-09-2024)"
+func (p Period) Label() string {
+	return fmt.Sprintf("%s (%s s/d %s)", p.Name, p.StartDate.Format("02-01-2006"), p.EndDate.Format("02-01-2006"))
+}
+
+// ProductFigure adalah angka keuangan sebuah produk pada satu periode, dipakai sebagai pengganti
+// data produk saat nilai kriteria periode tersebut dihitung. Harga, profit per unit, stok dan jumlah terjual
+// memakai integer seperti tabel produk, sedangkan revenue (harga x jumlah terjual) memakai bigint
+type ProductFigure struct {
+	ID           int             `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	PeriodID     int             `gorm:"integer;not null;uniqueIndex:idx_product_figures_period_product" json:"period_id"`
+	ProductID    int             `gorm:"integer;not null;uniqueIndex:idx_product_figures_period_product" json:"product_id"`
+	PurchaseCost int             `gorm:"type:integer" json:"purchase_cost"`
+	PriceSale    int             `gorm:"type:integer" json:"price_sale"`
+	Profit       int             `gorm:"type:integer" json:"profit"`
+	Stock        int             `gorm:"type:integer" json:"stock"`
+	Sold         int             `gorm:"type:integer" json:"sold"`
+	Revenue      int64           `gorm:"type:bigint" json:"revenue"`
+	Period       Period          `gorm:"foreignkey:PeriodID" json:"-"`
+	Product      product.Product `gorm:"foreignkey:ProductID" json:"-"`
+	CreatedAt    time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (f ProductFigure) Figures() product.Figures {
+	return product.Figures{
+		PurchaseCost: float64(f.PurchaseCost),
+		PriceSale:    float64(f.PriceSale),
+		Profit:       float64(f.Profit),
+		Stock:        float64(f.Stock),
+		Sold:         float64(f.Sold),
+		Revenue:      float64(f.Revenue),
+	}
+}
+
+// RequestPeriod: start_date dan end_date berformat YYYY-MM-DD
+type RequestPeriod struct {
+	Name      string `json:"name"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// RequestFigure: revenue kosong dihitung dari price_sale x sold
+type RequestFigure struct {
+	ProductID    int    `json:"product_id"`
+	PurchaseCost int    `json:"purchase_cost"`
+	PriceSale    int    `json:"price_sale"`
+	Stock        int    `json:"stock"`
+	Sold         int    `json:"sold"`
+	Revenue      *int64 `json:"revenue"`
+}
+
+type ResponsePeriod struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type ResponseFigure struct {
+	ProductID    int    `json:"product_id"`
+	ProductName  string `json:"product_name"`
+	PurchaseCost int    `json:"purchase_cost"`
+	PriceSale    int    `json:"price_sale"`
+	Profit       int    `json:"profit"`
+	Stock        int    `json:"stock"`
+	Sold         int    `json:"sold"`
+	Revenue      int64  `json:"revenue"`
+}
+
+type ResponsePeriodDetail struct {
+	ResponsePeriod
+	Figures []ResponseFigure `json:"figures"`
+}
+
+func toResponsePeriod(p Period) ResponsePeriod {
+	return ResponsePeriod{
+		ID:        p.ID,
+		Name:      p.Name,
+		StartDate: p.StartDate.Format(dateLayout),
+		EndDate:   p.EndDate.Format(dateLayout),
+	}
+}
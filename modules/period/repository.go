@@ -0,0 +1,79 @@
+package period
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	GetAllPeriodRepository() (result []Period, err error)
+	GetPeriodByIdRepository(periodID int) (result Period, err error)
+	CreatePeriodRepository(period *Period) (err error)
+	DeletePeriodRepository(period *Period) (err error)
+	GetFiguresByPeriodIdRepository(periodID int) (result []ProductFigure, err error)
+	UpsertFiguresRepository(figures []ProductFigure) (err error)
+}
+
+// batchSize adalah jumlah baris per INSERT saat menyimpan angka produk per periode
+const batchSize = 500
+
+type periodRepository struct {
+	DB *gorm.DB
+}
+
+func NewPeriodRepository(db *gorm.DB) Repository {
+	return &periodRepository{
+		DB: db,
+	}
+}
+
+func (r *periodRepository) GetAllPeriodRepository() (result []Period, err error) {
+	err = r.DB.Order("start_date DESC, id DESC").Find(&result).Error
+	return result, err
+}
+
+func (r *periodRepository) GetPeriodByIdRepository(periodID int) (result Period, err error) {
+	err = r.DB.First(&result, periodID).Error
+	return result, err
+}
+
+func (r *periodRepository) CreatePeriodRepository(period *Period) (err error) {
+	err = r.DB.Create(period).Error
+	return err
+}
+
+// DeletePeriodRepository menghapus periode beserta angka produk dan nilai kriteria periode tersebut,
+// laporan yang sudah dibuat tetap menyimpan nama periodenya
+func (r *periodRepository) DeletePeriodRepository(period *Period) (err error) {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err = tx.Where("period_id = ?", period.ID).Delete(&ProductFigure{}).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Exec("DELETE FROM criteria_scores WHERE period_id = ?", period.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(period).Error
+	})
+}
+
+func (r *periodRepository) GetFiguresByPeriodIdRepository(periodID int) (result []ProductFigure, err error) {
+	err = r.DB.Preload("Product").Where("period_id = ?", periodID).Order("product_id ASC").Find(&result).Error
+	return result, err
+}
+
+// UpsertFiguresRepository menyimpan angka produk per periode per batch dalam satu transaksi, angka yang sudah
+// ada untuk pasangan periode-produk yang sama diperbarui (unique index period_id, product_id)
+func (r *periodRepository) UpsertFiguresRepository(figures []ProductFigure) (err error) {
+	if len(figures) == 0 {
+		return nil
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "period_id"}, {Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"purchase_cost", "price_sale", "profit", "stock", "sold", "revenue", "updated_at"}),
+		}).CreateInBatches(&figures, batchSize).Error
+	})
+}
@@ -0,0 +1,28 @@
+package period
+
+import (
+	"backend-profitrack/middleware"
+	"backend-profitrack/modules/inventory"
+	"backend-profitrack/modules/product"
+	"backend-profitrack/modules/sales"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	repo := NewPeriodRepository(db)
+	productRepo := product.NewProductRepository(db)
+	salesRepo := sales.NewSalesRepository(db)
+	inventoryRepo := inventory.NewInventoryRepository(db)
+	service := NewPeriodService(repo, productRepo, salesRepo, inventoryRepo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.GET("/periods", service.GetAllPeriodService)
+	api.GET("/periods/:id", service.GetPeriodByIdService)
+	api.POST("/periods", service.CreatePeriodService)
+	api.DELETE("/periods/:id", service.DeletePeriodService)
+	api.POST("/periods/:id/capture", service.CapturePeriodFiguresService)
+	api.PUT("/periods/:id/figures", service.UpdatePeriodFiguresService)
+}
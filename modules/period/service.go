@@ -0,0 +1,380 @@
+package period
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/inventory"
+	"backend-profitrack/modules/product"
+	"backend-profitrack/modules/sales"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Service interface {
+	GetAllPeriodService(ctx *gin.Context)
+	GetPeriodByIdService(ctx *gin.Context)
+	CreatePeriodService(ctx *gin.Context)
+	DeletePeriodService(ctx *gin.Context)
+	CapturePeriodFiguresService(ctx *gin.Context)
+	UpdatePeriodFiguresService(ctx *gin.Context)
+}
+
+type periodService struct {
+	repository          Repository
+	productRepository   product.Repository
+	salesRepository     sales.Repository
+	inventoryRepository inventory.Repository
+}
+
+func NewPeriodService(repo Repository, productRepo product.Repository, salesRepo sales.Repository, inventoryRepo inventory.Repository) Service {
+	return &periodService{
+		repository:          repo,
+		productRepository:   productRepo,
+		salesRepository:     salesRepo,
+		inventoryRepository: inventoryRepo,
+	}
+}
+
+// Resolve mengambil periode dari nilai query period_id, nilai kosong atau 0 berarti tanpa periode
+// (data produk saat ini) dan mengembalikan Period kosong
+func Resolve(repo Repository, value string) (result Period, statusCode int, err error) {
+	if value == "" || value == "0" {
+		return result, http.StatusOK, nil
+	}
+
+	periodID, err := strconv.Atoi(value)
+	if err != nil || periodID < 0 {
+		return result, http.StatusBadRequest, errors.New("period_id tidak sesuai")
+	}
+
+	result, err = repo.GetPeriodByIdRepository(periodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return result, http.StatusNotFound, fmt.Errorf("Periode dengan ID:%d tidak ditemukan", periodID)
+		}
+		return result, http.StatusInternalServerError, err
+	}
+	return result, http.StatusOK, nil
+}
+
+func (service *periodService) GetAllPeriodService(ctx *gin.Context) {
+	periods, err := service.repository.GetAllPeriodRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data periode"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(periods) == 0 {
+		response := map[string]string{"message": "data periode masih kosong"}
+		helpers.ResponseJSON(ctx, http.StatusOK, response)
+		return
+	}
+
+	result := make([]ResponsePeriod, 0, len(periods))
+	for _, getPeriod := range periods {
+		result = append(result, toResponsePeriod(getPeriod))
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+// GetPeriodByIdService menampilkan periode beserta angka setiap produk pada periode tersebut
+func (service *periodService) GetPeriodByIdService(ctx *gin.Context) {
+	getPeriod, statusCode, err := service.findPeriod(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	figures, err := service.repository.GetFiguresByPeriodIdRepository(getPeriod.ID)
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data angka produk periode"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	result := ResponsePeriodDetail{
+		ResponsePeriod: toResponsePeriod(getPeriod),
+		Figures:        make([]ResponseFigure, 0, len(figures)),
+	}
+	for _, figure := range figures {
+		result.Figures = append(result.Figures, ResponseFigure{
+			ProductID:    figure.ProductID,
+			ProductName:  figure.Product.Name,
+			PurchaseCost: figure.PurchaseCost,
+			PriceSale:    figure.PriceSale,
+			Profit:       figure.Profit,
+			Stock:        figure.Stock,
+			Sold:         figure.Sold,
+			Revenue:      figure.Revenue,
+		})
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+func (service *periodService) CreatePeriodService(ctx *gin.Context) {
+	var request RequestPeriod
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		response := map[string]string{"error": "nama periode harus diisi"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	startDate, err := time.ParseInLocation(dateLayout, request.StartDate, time.Local)
+	if err != nil {
+		response := map[string]string{"error": "format start_date harus YYYY-MM-DD"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	endDate, err := time.ParseInLocation(dateLayout, request.EndDate, time.Local)
+	if err != nil {
+		response := map[string]string{"error": "format end_date harus YYYY-MM-DD"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if endDate.Before(startDate) {
+		response := map[string]string{"error": "end_date tidak boleh sebelum start_date"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	newPeriod := Period{
+		Name:      request.Name,
+		StartDate: startDate,
+		EndDate:   endDate,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err = service.repository.CreatePeriodRepository(&newPeriod)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			response := map[string]string{"error": "nama periode sudah ada"}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+		response := map[string]string{"error": "gagal menyimpan data periode"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusCreated, toResponsePeriod(newPeriod))
+}
+
+func (service *periodService) DeletePeriodService(ctx *gin.Context) {
+	getPeriod, statusCode, err := service.findPeriod(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	err = service.repository.DeletePeriodRepository(&getPeriod)
+	if err != nil {
+		response := map[string]string{"error": "gagal menghapus data periode"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := map[string]string{"message": fmt.Sprintf("Periode %s berhasil dihapus", getPeriod.Name)}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+// CapturePeriodFiguresService mengisi angka setiap produk pada periode dari data transaksi: sold dan revenue
+// dari penjualan pada periode, stok dari saldo jurnal stok pada akhir periode, sedangkan harga beli, harga jual
+// dan profit memakai data produk saat capture dijalankan (riwayat harga tidak disimpan) dan disebutkan pada
+// response. Angka yang sudah ada ditimpa
+func (service *periodService) CapturePeriodFiguresService(ctx *gin.Context) {
+	getPeriod, statusCode, err := service.findPeriod(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	products, err := service.productRepository.GetAllProductRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data produk"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(products) == 0 {
+		response := map[string]string{"error": "tidak ada data produk"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	periodRange := getPeriod.Range()
+	summaries, err := service.salesRepository.SummarizeSalesRepository(periodRange)
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data penjualan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	changes, err := service.inventoryRepository.SumChangesSinceRepository(periodRange.To)
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data pergerakan stok"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	now := time.Now()
+	periodFigures := sales.PeriodFigures(products, summaries)
+	figures := make([]ProductFigure, 0, len(products))
+	for _, getProduct := range products {
+		productFigures := periodFigures[getProduct.ID]
+		figures = append(figures, ProductFigure{
+			PeriodID:     getPeriod.ID,
+			ProductID:    getProduct.ID,
+			PurchaseCost: getProduct.PurchaseCost,
+			PriceSale:    getProduct.PriceSale,
+			Profit:       getProduct.Profit,
+			Stock:        max(getProduct.Stock-changes[getProduct.ID], 0),
+			Sold:         int(productFigures.Sold),
+			Revenue:      int64(productFigures.Revenue),
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+	}
+
+	err = service.repository.UpsertFiguresRepository(figures)
+	if err != nil {
+		response := map[string]string{"error": "gagal menyimpan data angka produk periode"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message":                fmt.Sprintf("angka %d produk pada periode %s berhasil disimpan", len(figures), getPeriod.Name),
+		"current_product_fields": []string{"purchase_cost", "price_sale", "profit"},
+		"note":                   "harga beli, harga jual dan profit diambil dari data produk saat ini, bukan dari riwayat periode. Ubah lewat PUT /periods/:id/figures jika berbeda",
+	}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+// UpdatePeriodFiguresService mengisi atau memperbarui angka produk periode secara manual,
+// mis. untuk periode lama yang belum memiliki transaksi penjualan dan jurnal stok
+func (service *periodService) UpdatePeriodFiguresService(ctx *gin.Context) {
+	getPeriod, statusCode, err := service.findPeriod(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	var request []RequestFigure
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if len(request) == 0 {
+		response := map[string]string{"error": "data angka produk harus diisi"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	products, err := service.productRepository.GetAllProductRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data produk"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	productIDs := make(map[int]bool, len(products))
+	for _, getProduct := range products {
+		productIDs[getProduct.ID] = true
+	}
+
+	now := time.Now()
+	seen := make(map[int]bool, len(request))
+	figures := make([]ProductFigure, 0, len(request))
+	for _, figure := range request {
+		if !productIDs[figure.ProductID] {
+			response := map[string]string{"error": fmt.Sprintf("Produk dengan ID:%d tidak ditemukan", figure.ProductID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+
+		if seen[figure.ProductID] {
+			response := map[string]string{"error": fmt.Sprintf("produk ID:%d diisi lebih dari satu kali", figure.ProductID)}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+		seen[figure.ProductID] = true
+
+		revenue := int64(figure.PriceSale) * int64(figure.Sold)
+		if figure.Revenue != nil {
+			revenue = *figure.Revenue
+		}
+
+		if figure.PurchaseCost <= 0 || figure.PriceSale <= 0 || figure.Stock < 0 || figure.Sold < 0 || revenue < 0 {
+			response := map[string]string{"error": fmt.Sprintf("angka produk ID:%d tidak valid", figure.ProductID)}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+
+		figures = append(figures, ProductFigure{
+			PeriodID:     getPeriod.ID,
+			ProductID:    figure.ProductID,
+			PurchaseCost: figure.PurchaseCost,
+			PriceSale:    figure.PriceSale,
+			Profit:       figure.PriceSale - figure.PurchaseCost,
+			Stock:        figure.Stock,
+			Sold:         figure.Sold,
+			Revenue:      revenue,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+	}
+
+	err = service.repository.UpsertFiguresRepository(figures)
+	if err != nil {
+		response := map[string]string{"error": "gagal menyimpan data angka produk periode"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message":                fmt.Sprintf("angka %d produk pada periode %s berhasil disimpan", len(figures), getPeriod.Name),
+		"current_product_fields": []string{"purchase_cost", "price_sale", "profit"},
+		"note":                   "harga beli, harga jual dan profit diambil dari data produk saat ini, bukan dari riwayat periode. Ubah lewat PUT /periods/:id/figures jika berbeda",
+	}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+func (service *periodService) findPeriod(ctx *gin.Context) (Period, int, error) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return Period{}, http.StatusBadRequest, errors.New("ID tidak sesuai")
+	}
+
+	getPeriod, err := service.repository.GetPeriodByIdRepository(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return getPeriod, http.StatusNotFound, fmt.Errorf("Periode dengan ID:%d tidak ditemukan", id)
+		}
+		return getPeriod, http.StatusInternalServerError, err
+	}
+	return getPeriod, http.StatusOK, nil
+}
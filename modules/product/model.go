@@ -4,6 +4,9 @@ import (
 	"time"
 )
 
+// Product.Sold adalah jumlah terjual awal ditambah seluruh transaksi penjualan pada modul sales dikurangi retur dan
+// Product.Stock adalah stok awal ditambah seluruh pergerakan pada modul inventory, keduanya diperbarui
+// otomatis saat transaksi dicatat atau dihapus
 type Product struct {
 	ID           int       `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
 	Name         string    `gorm:"varchar(50);UNIQUE;not null" json:"name"`
@@ -11,12 +14,23 @@ type Product struct {
 	PriceSale    int       `gorm:"type:integer;not null" json:"price_sale"`
 	Profit       int       `gorm:"type:integer" json:"profit"`
 	Unit         string    `gorm:"varchar(25)" json:"unit"`
-	Stock        int       `gorm:"type:smallint" json:"stock"`
-	Sold         int       `gorm:"type:smallint" json:"sold"`
+	Stock        int       `gorm:"type:integer" json:"stock"`
+	Sold         int       `gorm:"type:integer" json:"sold"`
 	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
+// RequestUpdateProduct memakai pointer untuk stock dan sold agar field yang dikirim dapat ditolak,
+// keduanya hanya berubah melalui transaksi penjualan dan pergerakan stok
+type RequestUpdateProduct struct {
+	Name         string `json:"name"`
+	PurchaseCost int    `json:"purchase_cost"`
+	PriceSale    int    `json:"price_sale"`
+	Unit         string `json:"unit"`
+	Stock        *int   `json:"stock"`
+	Sold         *int   `json:"sold"`
+}
+
 type ResponseProduct struct {
 	ID           int    `json:"id"`
 	Name         string `json:"name"`
@@ -35,3 +49,44 @@ type ExcelProduct struct {
 	Stock        int    `validate:"required"`
 	Sold         int    `validate:"required"`
 }
+
+// Figures adalah angka produk yang dapat dipakai sebagai variabel pada rumus kriteria,
+// tanpa periode Revenue diperkirakan dari harga jual saat ini x jumlah terjual
+type Figures struct {
+	PurchaseCost float64
+	PriceSale    float64
+	Profit       float64
+	Stock        float64
+	Sold         float64
+	Revenue      float64
+}
+
+func (p Product) Figures() Figures {
+	return Figures{
+		PurchaseCost: float64(p.PurchaseCost),
+		PriceSale:    float64(p.PriceSale),
+		Profit:       float64(p.Profit),
+		Stock:        float64(p.Stock),
+		Sold:         float64(p.Sold),
+		Revenue:      float64(p.PriceSale) * float64(p.Sold),
+	}
+}
+
+// FormulaVariables adalah daftar variabel yang boleh dipakai pada rumus kriteria,
+// field baru cukup ditambahkan di sini agar dapat dipakai pada rumus
+var FormulaVariables = map[string]func(figures Figures) float64{
+	"purchase_cost": func(figures Figures) float64 { return figures.PurchaseCost },
+	"price_sale":    func(figures Figures) float64 { return figures.PriceSale },
+	"profit":        func(figures Figures) float64 { return figures.Profit },
+	"stock":         func(figures Figures) float64 { return figures.Stock },
+	"sold":          func(figures Figures) float64 { return figures.Sold },
+	"revenue":       func(figures Figures) float64 { return figures.Revenue },
+}
+
+func (f Figures) Variables() map[string]float64 {
+	variables := make(map[string]float64, len(FormulaVariables))
+	for name, value := range FormulaVariables {
+		variables[name] = value(f)
+	}
+	return variables
+}
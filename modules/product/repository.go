@@ -11,7 +11,7 @@ type Repository interface {
 	GetProductByIdRepository(productID int) (product Product, err error)
 	UpdateProductRepository(product *Product) (err error)
 	DeleteProductRepository(product *Product) (err error)
-	BulkCreateProductRepository(products []Product) error
+	BulkCreateProductRepository(products []Product, progress func(inserted int, total int)) error
 }
 
 type productRepository struct {
@@ -29,8 +29,25 @@ func (r *productRepository) CountProductsRepository() (total int64, err error) {
 	return total, err
 }
 
-func (r *productRepository) BulkCreateProductRepository(products []Product) error {
-	return r.DB.Create(&products).Error
+// importBatchSize adalah jumlah produk per INSERT saat import
+const importBatchSize = 100
+
+// BulkCreateProductRepository menyimpan seluruh produk dalam satu transaksi, progress (boleh nil)
+// dipanggil setiap satu batch selesai ditulis
+func (r *productRepository) BulkCreateProductRepository(products []Product, progress func(inserted int, total int)) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(products); start += importBatchSize {
+			end := min(start+importBatchSize, len(products))
+			if err := tx.Create(products[start:end]).Error; err != nil {
+				return err
+			}
+
+			if progress != nil {
+				progress(end, len(products))
+			}
+		}
+		return nil
+	})
 }
 
 func (r *productRepository) GetAllProductRepository() (result []Product, err error) {
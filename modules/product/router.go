@@ -20,5 +20,6 @@ func Initiator(router *gin.Engine, db *gorm.DB) {
 	api.PUT("/products/:id", service.UpdateProductService)
 	api.DELETE("/products/:id", service.DeleteProductService)
 	api.POST("/products/import", service.ImportExcelService)
+	api.POST("/products/import/stream", service.ImportExcelStreamService)
 	api.GET("/products/export", service.ExportExcelService)
 }
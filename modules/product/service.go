@@ -23,6 +23,7 @@ type Service interface {
 	UpdateProductService(ctx *gin.Context)
 	DeleteProductService(ctx *gin.Context)
 	ImportExcelService(ctx *gin.Context)
+	ImportExcelStreamService(ctx *gin.Context)
 	ExportExcelService(ctx *gin.Context)
 }
 
@@ -87,12 +88,13 @@ func (service *productService) CreateProductService(ctx *gin.Context) {
 		return
 	}
 
+	// stok dan sold boleh 0, pembelian dan penjualan berikutnya dicatat lewat modul inventory dan sales
 	if newProduct.Name == "" ||
 		newProduct.PurchaseCost == 0 ||
 		newProduct.PriceSale == 0 ||
 		newProduct.Unit == "" ||
-		newProduct.Stock == 0 ||
-		newProduct.Sold == 0 {
+		newProduct.Stock < 0 ||
+		newProduct.Sold < 0 {
 		response = map[string]string{"error": "semua field harus diisi dengan nilai yang valid"}
 		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
 		return
@@ -156,7 +158,7 @@ func (service *productService) GetProductByIdService(ctx *gin.Context) {
 }
 
 func (service *productService) UpdateProductService(ctx *gin.Context) {
-	var product Product
+	var product RequestUpdateProduct
 	response := map[string]string{}
 
 	id, err := strconv.Atoi(ctx.Param("id"))
@@ -172,6 +174,12 @@ func (service *productService) UpdateProductService(ctx *gin.Context) {
 		return
 	}
 
+	if product.Stock != nil || product.Sold != nil {
+		response = map[string]string{"error": "stock dan sold tidak dapat diubah langsung, gunakan /api/sales untuk penjualan dan /api/inventory untuk pembelian, retur atau penyesuaian stok"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
 	existingProduct, err := service.repository.GetProductByIdRepository(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -187,15 +195,13 @@ func (service *productService) UpdateProductService(ctx *gin.Context) {
 	if product.Name == "" ||
 		product.PriceSale == 0 ||
 		product.PurchaseCost == 0 ||
-		product.Unit == "" ||
-		product.Stock == 0 ||
-		product.Sold == 0 {
+		product.Unit == "" {
 		response = map[string]string{"error": "semua field harus diisi dengan nilai yang valid"}
 		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
 		return
 	}
 
-	if existingProduct.Name == product.Name && existingProduct.PriceSale == product.PriceSale && existingProduct.PurchaseCost == product.PurchaseCost && existingProduct.Profit == product.Profit && existingProduct.Unit == product.Unit && existingProduct.Stock == product.Stock {
+	if existingProduct.Name == product.Name && existingProduct.PriceSale == product.PriceSale && existingProduct.PurchaseCost == product.PurchaseCost && existingProduct.Unit == product.Unit {
 		response = map[string]string{"error": "masukkan minimal satu data yang baru"}
 		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
 		return
@@ -206,8 +212,6 @@ func (service *productService) UpdateProductService(ctx *gin.Context) {
 	existingProduct.PurchaseCost = product.PurchaseCost
 	existingProduct.Profit = product.PriceSale - product.PurchaseCost
 	existingProduct.Unit = product.Unit
-	existingProduct.Stock = product.Stock
-	existingProduct.Sold = product.Sold
 	existingProduct.UpdatedAt = time.Now()
 
 	err = service.repository.UpdateProductRepository(&existingProduct)
@@ -262,39 +266,102 @@ func (service *productService) DeleteProductService(ctx *gin.Context) {
 }
 
 func (service *productService) ImportExcelService(ctx *gin.Context) {
-	file, err := ctx.FormFile("file")
+	rows, statusCode, err := readImportRows(ctx)
 	if err != nil {
-		helpers.ResponseJSON(ctx, http.StatusBadRequest, gin.H{"error": "File tidak ditemukan"})
+		helpers.ResponseJSON(ctx, statusCode, gin.H{"error": err.Error()})
 		return
 	}
 
-	if filepath.Ext(file.Filename) != ".xlsx" {
-		helpers.ResponseJSON(ctx, http.StatusBadRequest, gin.H{"error": "Format file harus xlsx"})
+	products, err := parseImportRows(rows, nil)
+	if err != nil {
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Bulk insert
+	if err = service.repository.BulkCreateProductRepository(products, nil); err != nil {
+		statusCode, message := importError(err)
+		helpers.ResponseJSON(ctx, statusCode, gin.H{"error": message})
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, gin.H{"message": fmt.Sprintf("Berhasil import %d produk", len(products))})
+}
+
+// ImportExcelStreamService sama dengan ImportExcelService tetapi mengirim progres sebagai Server-Sent Events:
+// "parsed" (baris dibaca), "inserted" (baris disimpan), lalu "result" atau "error" sebagai event terakhir
+func (service *productService) ImportExcelStreamService(ctx *gin.Context) {
+	rows, statusCode, err := readImportRows(ctx)
+	if err != nil {
+		helpers.ResponseJSON(ctx, statusCode, gin.H{"error": err.Error()})
 		return
 	}
 
+	events := make(chan helpers.Event, 16)
+	send := helpers.EventSender(ctx.Request.Context(), events)
+
+	go func() {
+		defer close(events)
+
+		products, err := parseImportRows(rows, func(parsed int, total int) {
+			send("parsed", gin.H{"done": parsed, "total": total})
+		})
+		if err != nil {
+			send("error", gin.H{"error": err.Error()})
+			return
+		}
+
+		err = service.repository.BulkCreateProductRepository(products, func(inserted int, total int) {
+			send("inserted", gin.H{"done": inserted, "total": total})
+		})
+		if err != nil {
+			_, message := importError(err)
+			send("error", gin.H{"error": message})
+			return
+		}
+
+		send("result", gin.H{"message": fmt.Sprintf("Berhasil import %d produk", len(products))})
+	}()
+
+	helpers.StreamEvents(ctx, events)
+}
+
+// readImportRows membaca baris Sheet1 dari file xlsx yang diunggah pada field "file"
+func readImportRows(ctx *gin.Context) ([][]string, int, error) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("File tidak ditemukan")
+	}
+
+	if filepath.Ext(file.Filename) != ".xlsx" {
+		return nil, http.StatusBadRequest, errors.New("Format file harus xlsx")
+	}
+
 	// Simpan file sementara
 	tempFile := fmt.Sprintf("temp/%d-%s", time.Now().Unix(), file.Filename)
 	if err = ctx.SaveUploadedFile(file, tempFile); err != nil {
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan file"})
-		return
+		return nil, http.StatusInternalServerError, errors.New("Gagal menyimpan file")
 	}
 	defer os.Remove(tempFile)
 
 	// Baca file Excel
 	xlsx, err := excelize.OpenFile(tempFile)
 	if err != nil {
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membaca file Excel"})
-		return
+		return nil, http.StatusInternalServerError, errors.New("Gagal membaca file Excel")
 	}
 	defer xlsx.Close()
 
 	rows, err := xlsx.GetRows("Sheet1")
 	if err != nil {
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membaca sheet"})
-		return
+		return nil, http.StatusInternalServerError, errors.New("Gagal membaca sheet")
 	}
 
+	return rows, http.StatusOK, nil
+}
+
+// parseImportRows mengubah baris Excel menjadi produk, progress (boleh nil) dipanggil setiap 50 baris
+func parseImportRows(rows [][]string, progress func(parsed int, total int)) ([]Product, error) {
+	total := len(rows) - 1
 	var products []Product
 	for i, row := range rows {
 		if i == 0 {
@@ -303,10 +370,7 @@ func (service *productService) ImportExcelService(ctx *gin.Context) {
 
 		// Periksa jumlah kolom yang dibutuhkan (6 kolom sesuai format)
 		if len(row) < 6 {
-			helpers.ResponseJSON(ctx, http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Format tidak valid pada baris %d: jumlah kolom kurang dari 6", i+1),
-			})
-			return
+			return nil, fmt.Errorf("Format tidak valid pada baris %d: jumlah kolom kurang dari 6", i+1)
 		}
 
 		// Fungsi helper untuk membersihkan string angka
@@ -326,42 +390,27 @@ func (service *productService) ImportExcelService(ctx *gin.Context) {
 		// Konversi ke integer dengan validasi
 		purchaseCost, err := strconv.Atoi(cleanPurchaseCost)
 		if err != nil {
-			helpers.ResponseJSON(ctx, http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Format harga beli tidak valid pada baris %d: %s", i+1, row[1]),
-			})
-			return
+			return nil, fmt.Errorf("Format harga beli tidak valid pada baris %d: %s", i+1, row[1])
 		}
 
 		priceSale, err := strconv.Atoi(cleanPriceSale)
 		if err != nil {
-			helpers.ResponseJSON(ctx, http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Format harga jual tidak valid pada baris %d: %s", i+1, row[2]),
-			})
-			return
+			return nil, fmt.Errorf("Format harga jual tidak valid pada baris %d: %s", i+1, row[2])
 		}
 
 		stock, err := strconv.Atoi(cleanStock)
 		if err != nil {
-			helpers.ResponseJSON(ctx, http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Format stok tidak valid pada baris %d: %s", i+1, row[4]),
-			})
-			return
+			return nil, fmt.Errorf("Format stok tidak valid pada baris %d: %s", i+1, row[4])
 		}
 
 		sold, err := strconv.Atoi(cleanSold)
 		if err != nil {
-			helpers.ResponseJSON(ctx, http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Format stok terjual tidak valid pada baris %d: %s", i+1, row[5]),
-			})
-			return
+			return nil, fmt.Errorf("Format stok terjual tidak valid pada baris %d: %s", i+1, row[5])
 		}
 
 		// Validasi angka negatif
 		if purchaseCost < 0 || priceSale < 0 || stock < 0 || sold < 0 {
-			helpers.ResponseJSON(ctx, http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Nilai tidak boleh negatif pada baris %d", i+1),
-			})
-			return
+			return nil, fmt.Errorf("Nilai tidak boleh negatif pada baris %d", i+1)
 		}
 
 		product := Product{
@@ -376,19 +425,20 @@ func (service *productService) ImportExcelService(ctx *gin.Context) {
 			UpdatedAt:    time.Now(),
 		}
 		products = append(products, product)
-	}
 
-	// Bulk insert
-	if err = service.repository.BulkCreateProductRepository(products); err != nil {
-		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
-			helpers.ResponseJSON(ctx, http.StatusBadRequest, gin.H{"error": "Beberapa produk sudah ada (duplikat nama produk)"})
-			return
+		if progress != nil && (i%50 == 0 || i == total) {
+			progress(i, total)
 		}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data"})
-		return
 	}
 
-	helpers.ResponseJSON(ctx, http.StatusOK, gin.H{"message": fmt.Sprintf("Berhasil import %d produk", len(products))})
+	return products, nil
+}
+
+func importError(err error) (int, string) {
+	if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+		return http.StatusBadRequest, "Beberapa produk sudah ada (duplikat nama produk)"
+	}
+	return http.StatusInternalServerError, "Gagal menyimpan data"
 }
 
 func (service *productService) ExportExcelService(ctx *gin.Context) {
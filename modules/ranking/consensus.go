@@ -0,0 +1,150 @@
+package ranking
+
+import (
+	"sort"
+	"strings"
+)
+
+func init() {
+	RegisterConsensus(Borda{})
+	RegisterConsensus(Copeland{})
+	RegisterConsensus(AverageRank{})
+}
+
+// Consensus menggabungkan skor akhir beberapa metode menjadi satu skor,
+// scores[s][i] adalah skor akhir produk ke-i pada metode sumber ke-s dan skor hasil makin besar makin baik
+type Consensus interface {
+	Name() string
+	Parameters() map[string]string
+	Combine(scores [][]float64) []float64
+}
+
+var consensusRegistry = make(map[string]Consensus)
+
+func RegisterConsensus(consensus Consensus) {
+	consensusRegistry[strings.ToUpper(consensus.Name())] = consensus
+}
+
+func GetConsensus(name string) (Consensus, bool) {
+	consensus, ok := consensusRegistry[strings.ToUpper(name)]
+	return consensus, ok
+}
+
+func AllConsensus() []Consensus {
+	var result []Consensus
+	for _, consensus := range consensusRegistry {
+		result = append(result, consensus)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+// Borda memberi poin n - peringkat pada setiap metode lalu menjumlahkannya
+type Borda struct{}
+
+func (Borda) Name() string {
+	return "BORDA"
+}
+
+func (Borda) Parameters() map[string]string {
+	return map[string]string{
+		"points":      "jumlah produk - peringkat",
+		"ties":        "peringkat rata-rata",
+		"aggregation": "jumlah poin semua metode sumber",
+	}
+}
+
+func (Borda) Combine(scores [][]float64) []float64 {
+	result := make([]float64, productCount(scores))
+	n := float64(len(result))
+	for _, source := range scores {
+		for i, rank := range AverageRanks(source) {
+			result[i] += n - rank
+		}
+	}
+	return result
+}
+
+// Copeland membandingkan setiap pasangan produk: menang jika lebih banyak metode menempatkannya lebih tinggi
+type Copeland struct{}
+
+func (Copeland) Name() string {
+	return "COPELAND"
+}
+
+func (Copeland) Parameters() map[string]string {
+	return map[string]string{
+		"comparison":  "mayoritas metode sumber per pasangan produk",
+		"points":      "menang +1, seri 0, kalah -1",
+		"aggregation": "jumlah poin seluruh pasangan",
+	}
+}
+
+func (Copeland) Combine(scores [][]float64) []float64 {
+	result := make([]float64, productCount(scores))
+	for i := range result {
+		for j := i + 1; j < len(result); j++ {
+			var votes int
+			for _, source := range scores {
+				switch {
+				case source[i] > source[j]:
+					votes++
+				case source[i] < source[j]:
+					votes--
+				}
+			}
+
+			switch {
+			case votes > 0:
+				result[i]++
+				result[j]--
+			case votes < 0:
+				result[i]--
+				result[j]++
+			}
+		}
+	}
+	return result
+}
+
+// AverageRank mengurutkan produk berdasarkan rata-rata peringkat, skor = n + 1 - rata-rata peringkat
+type AverageRank struct{}
+
+func (AverageRank) Name() string {
+	return "AVERAGE-RANK"
+}
+
+func (AverageRank) Parameters() map[string]string {
+	return map[string]string{
+		"ties":        "peringkat rata-rata",
+		"aggregation": "jumlah produk + 1 - rata-rata peringkat",
+	}
+}
+
+func (AverageRank) Combine(scores [][]float64) []float64 {
+	result := make([]float64, productCount(scores))
+	if len(scores) == 0 {
+		return result
+	}
+
+	n := float64(len(result))
+	for _, source := range scores {
+		for i, rank := range AverageRanks(source) {
+			result[i] += rank / float64(len(scores))
+		}
+	}
+	for i := range result {
+		result[i] = n + 1 - result[i]
+	}
+	return result
+}
+
+func productCount(scores [][]float64) int {
+	if len(scores) == 0 {
+		return 0
+	}
+	return len(scores[0])
+}
@@ -0,0 +1,42 @@
+package ranking
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConsensusCombine(t *testing.T) {
+	// metode pertama: peringkat (1, 2, 3), metode kedua: peringkat (3, 1, 2)
+	disagree := [][]float64{{3, 2, 1}, {1, 3, 2}}
+	// peringkat rata-rata (1.5, 1.5, 3)
+	ties := [][]float64{{5, 5, 1}}
+
+	tests := []struct {
+		name      string
+		consensus Consensus
+		scores    [][]float64
+		want      []float64
+	}{
+		{"borda", Borda{}, disagree, []float64{2, 3, 1}},
+		{"borda skor kembar", Borda{}, ties, []float64{1.5, 1.5, 0}},
+		{"copeland", Copeland{}, disagree, []float64{0, 1, -1}},
+		{"copeland skor kembar", Copeland{}, ties, []float64{1, 1, -2}},
+		{"rata-rata peringkat", AverageRank{}, disagree, []float64{2, 2.5, 1.5}},
+		{"rata-rata peringkat skor kembar", AverageRank{}, ties, []float64{2.5, 2.5, 1}},
+		{"tanpa metode sumber", AverageRank{}, nil, []float64{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.consensus.Combine(test.scores)
+			if len(got) != len(test.want) {
+				t.Fatalf("jumlah skor = %d, want %d", len(got), len(test.want))
+			}
+			for i, want := range test.want {
+				if math.Abs(got[i]-want) > 1e-9 {
+					t.Errorf("skor produk %d = %v, want %v", i, got[i], want)
+				}
+			}
+		})
+	}
+}
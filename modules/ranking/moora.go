@@ -0,0 +1,56 @@
+package ranking
+
+func init() {
+	Register(MOORA{})
+}
+
+// MOORA menggunakan normalisasi vektor dan selisih jumlah benefit dengan cost
+type MOORA struct {
+	weightedByCriteria
+}
+
+func (MOORA) Name() string {
+	return "MOORA"
+}
+
+func (MOORA) Parameters() map[string]string {
+	return map[string]string{
+		"normalization": "vektor",
+		"weighting":     "normalisasi x bobot",
+		"aggregation":   "Yi = Σ benefit - Σ cost",
+	}
+}
+
+func (MOORA) Normalize(matrix Matrix) [][]float64 {
+	return vectorNormalize(matrix)
+}
+
+func (MOORA) Aggregate(matrix Matrix, weighted [][]float64) []float64 {
+	scores := make([]float64, len(weighted))
+	for i, row := range weighted {
+		for j, value := range row {
+			if matrix.Criteria[j].IsCost() {
+				scores[i] -= value
+			} else {
+				scores[i] += value
+			}
+		}
+	}
+	return scores
+}
+
+func (MOORA) TraceAggregation(matrix Matrix, weighted [][]float64) ([]map[string]float64, []map[string]float64) {
+	products := make([]map[string]float64, len(weighted))
+	for i, row := range weighted {
+		var benefit, cost float64
+		for j, value := range row {
+			if matrix.Criteria[j].IsCost() {
+				cost += value
+			} else {
+				benefit += value
+			}
+		}
+		products[i] = map[string]float64{"sum_benefit": benefit, "sum_cost": cost}
+	}
+	return nil, products
+}
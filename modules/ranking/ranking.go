@@ -0,0 +1,255 @@
+package ranking
+
+import (
+	"backend-profitrack/config"
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/criteria_score"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Criterion adalah satu kolom pada matriks keputusan
+type Criterion struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+	Type   string  `json:"type"`
+}
+
+func (c Criterion) IsCost() bool {
+	return strings.EqualFold(c.Type, "Cost")
+}
+
+// Matrix adalah matriks keputusan, baris = produk dan kolom = kriteria
+type Matrix struct {
+	ProductIDs []int
+	Criteria   []Criterion
+	Values     [][]float64
+}
+
+// Result menyimpan setiap tahap perhitungan untuk satu matriks keputusan
+type Result struct {
+	Normalized [][]float64
+	Weighted   [][]float64
+	Scores     []float64
+}
+
+// Algorithm adalah metode MCDM yang dipecah menjadi tiga tahap: normalisasi -> pembobotan -> agregasi
+type Algorithm interface {
+	Name() string
+	Parameters() map[string]string
+	Normalize(matrix Matrix) [][]float64
+	Weight(matrix Matrix, normalized [][]float64) [][]float64
+	Aggregate(matrix Matrix, weighted [][]float64) []float64
+}
+
+var registry = make(map[string]Algorithm)
+
+// Register mendaftarkan algoritma berdasarkan namanya, dipanggil dari init() setiap algoritma
+func Register(algorithm Algorithm) {
+	registry[strings.ToUpper(algorithm.Name())] = algorithm
+}
+
+func Get(name string) (Algorithm, bool) {
+	algorithm, ok := registry[strings.ToUpper(name)]
+	return algorithm, ok
+}
+
+func All() []Algorithm {
+	var result []Algorithm
+	for _, algorithm := range registry {
+		result = append(result, algorithm)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+func Run(algorithm Algorithm, matrix Matrix) Result {
+	normalized := algorithm.Normalize(matrix)
+	weighted := algorithm.Weight(matrix, normalized)
+	return Result{
+		Normalized: normalized,
+		Weighted:   weighted,
+		Scores:     algorithm.Aggregate(matrix, weighted),
+	}
+}
+
+// LoadMatrix mengambil data kriteria (setelah kebijakan total bobot diterapkan) dan nilai kriteria
+// periode periodID (0 untuk data produk saat ini) lalu menyusun matriks keputusan
+func LoadMatrix(criteriaRepo criteria.Repository, criteriaScoreRepo criteria_score.Repository, periodID int) ([]criteria.Criteria, Matrix, error) {
+	criteriaList, err := criteriaRepo.GetAllCriteriaRepository()
+	if err != nil {
+		return nil, Matrix{}, fmt.Errorf("gagal mengambil data kriteria: %v", err)
+	}
+
+	// total bobot harus 1 sebelum perhitungan dapat dilakukan
+	criteriaList, _, err = criteria.ApplyWeightPolicy(criteriaList, config.CriteriaWeightPolicy())
+	if err != nil {
+		return nil, Matrix{}, err
+	}
+
+	scores, err := criteriaScoreRepo.GetAllCriteriaScoreRepository(periodID)
+	if err != nil {
+		return nil, Matrix{}, fmt.Errorf("gagal mengambil data nilai kriteria: %v", err)
+	}
+
+	matrix, err := BuildMatrix(criteriaList, scores)
+	return criteriaList, matrix, err
+}
+
+// BuildMatrix menyusun matriks keputusan dari data kriteria dan nilai kriteria setiap produk
+func BuildMatrix(criteriaList []criteria.Criteria, scores []criteria_score.CriteriaScore) (Matrix, error) {
+	var matrix Matrix
+	if len(criteriaList) == 0 {
+		return matrix, fmt.Errorf("data kriteria masih kosong")
+	}
+
+	if len(scores) == 0 {
+		return matrix, fmt.Errorf("data nilai kriteria masih kosong")
+	}
+
+	columns := make(map[int]int)
+	for i, criterion := range criteriaList {
+		columns[criterion.ID] = i
+		matrix.Criteria = append(matrix.Criteria, Criterion{
+			ID:     criterion.ID,
+			Name:   criterion.Name,
+			Weight: criterion.Weight,
+			Type:   criterion.Type,
+		})
+	}
+
+	rows := make(map[int]int)
+	filled := make(map[int]int)
+	for _, score := range scores {
+		column, exists := columns[score.CriteriaID]
+		if !exists {
+			continue
+		}
+
+		row, exists := rows[score.ProductID]
+		if !exists {
+			row = len(matrix.ProductIDs)
+			rows[score.ProductID] = row
+			matrix.ProductIDs = append(matrix.ProductIDs, score.ProductID)
+			matrix.Values = append(matrix.Values, make([]float64, len(criteriaList)))
+		}
+
+		matrix.Values[row][column] = score.Score
+		filled[score.ProductID]++
+	}
+
+	for _, productID := range matrix.ProductIDs {
+		if filled[productID] != len(criteriaList) {
+			return matrix, fmt.Errorf("nilai kriteria produk ID:%d belum lengkap, perbarui data nilai kriteria", productID)
+		}
+	}
+
+	return matrix, nil
+}
+
+// weightedByCriteria adalah tahap pembobotan yang sama untuk SMART, MOORA dan TOPSIS: vij = wj * rij
+type weightedByCriteria struct{}
+
+func (weightedByCriteria) Weight(matrix Matrix, normalized [][]float64) [][]float64 {
+	weighted := make([][]float64, len(normalized))
+	for i, row := range normalized {
+		weighted[i] = make([]float64, len(row))
+		for j, value := range row {
+			weighted[i][j] = value * matrix.Criteria[j].Weight
+		}
+	}
+	return weighted
+}
+
+// vectorNormalize digunakan oleh MOORA dan TOPSIS: rij = xij / sqrt(Σ(xij^2))
+func vectorNormalize(matrix Matrix) [][]float64 {
+	norms := make([]float64, len(matrix.Criteria))
+	for _, row := range matrix.Values {
+		for j, value := range row {
+			norms[j] += value * value
+		}
+	}
+
+	normalized := make([][]float64, len(matrix.Values))
+	for i, row := range matrix.Values {
+		normalized[i] = make([]float64, len(row))
+		for j, value := range row {
+			if norms[j] != 0 {
+				normalized[i][j] = value / math.Sqrt(norms[j])
+			}
+		}
+	}
+	return normalized
+}
+
+// Ranks mengubah skor akhir menjadi peringkat (1 = skor tertinggi), skor yang sama
+// diurutkan berdasarkan urutan baris agar hasilnya selalu sama
+func Ranks(scores []float64) []int {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+
+	ranks := make([]int, len(scores))
+	for position, index := range order {
+		ranks[index] = position + 1
+	}
+	return ranks
+}
+
+// AverageRanks seperti Ranks, tetapi skor yang sama mendapat rata-rata peringkatnya (mis. 2.5)
+func AverageRanks(scores []float64) []float64 {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+
+	ranks := make([]float64, len(scores))
+	for start := 0; start < len(order); {
+		end := start
+		for end+1 < len(order) && scores[order[end+1]] == scores[order[start]] {
+			end++
+		}
+
+		average := float64(start+end)/2 + 1
+		for position := start; position <= end; position++ {
+			ranks[order[position]] = average
+		}
+		start = end + 1
+	}
+	return ranks
+}
+
+// WithWeights mengembalikan salinan matriks dengan bobot kriteria yang berbeda
+func (m Matrix) WithWeights(weights []float64) Matrix {
+	criteria := make([]Criterion, len(m.Criteria))
+	copy(criteria, m.Criteria)
+	for j := range criteria {
+		criteria[j].Weight = weights[j]
+	}
+
+	m.Criteria = criteria
+	return m
+}
+
+func (m Matrix) Weights() []float64 {
+	weights := make([]float64, len(m.Criteria))
+	for j, criterion := range m.Criteria {
+		weights[j] = criterion.Weight
+	}
+	return weights
+}
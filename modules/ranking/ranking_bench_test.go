@@ -0,0 +1,43 @@
+package ranking
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// benchmarkMatrix menyusun matriks keputusan acak dengan kriteria bawaan, seed tetap agar hasil dapat dibandingkan
+func benchmarkMatrix(productCount int) Matrix {
+	random := rand.New(rand.NewSource(1))
+	matrix := Matrix{
+		ProductIDs: make([]int, productCount),
+		Criteria: []Criterion{
+			{ID: 1, Name: "Return On Investment", Weight: 0.3, Type: "Benefit"},
+			{ID: 2, Name: "Net Profit Margin", Weight: 0.4, Type: "Benefit"},
+			{ID: 3, Name: "Rasio Efisiensi", Weight: 0.3, Type: "Cost"},
+		},
+		Values: make([][]float64, productCount),
+	}
+	for i := range matrix.ProductIDs {
+		matrix.ProductIDs[i] = i + 1
+		matrix.Values[i] = []float64{random.Float64(), random.Float64(), 0.5 + random.Float64()/2}
+	}
+	return matrix
+}
+
+func BenchmarkRun10kProducts(b *testing.B) {
+	matrix := benchmarkMatrix(10000)
+
+	for _, name := range []string{"SMART", "MOORA", "TOPSIS"} {
+		algorithm, ok := Get(name)
+		if !ok {
+			b.Fatalf("algoritma %s tidak terdaftar", name)
+		}
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Run(algorithm, matrix)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package ranking
+
+func init() {
+	Register(SMART{})
+}
+
+// SMART menggunakan nilai utility (min-max) dan penjumlahan terbobot
+type SMART struct {
+	weightedByCriteria
+}
+
+func (SMART) Name() string {
+	return "SMART"
+}
+
+func (SMART) Parameters() map[string]string {
+	return map[string]string{
+		"normalization": "utility min-max",
+		"weighting":     "utility x bobot",
+		"aggregation":   "jumlah nilai terbobot",
+	}
+}
+
+func (SMART) Normalize(matrix Matrix) [][]float64 {
+	minValues := make([]float64, len(matrix.Criteria))
+	maxValues := make([]float64, len(matrix.Criteria))
+	for i, row := range matrix.Values {
+		for j, value := range row {
+			if i == 0 || value < minValues[j] {
+				minValues[j] = value
+			}
+			if i == 0 || value > maxValues[j] {
+				maxValues[j] = value
+			}
+		}
+	}
+
+	normalized := make([][]float64, len(matrix.Values))
+	for i, row := range matrix.Values {
+		normalized[i] = make([]float64, len(row))
+		for j, value := range row {
+			if maxValues[j] == minValues[j] {
+				continue
+			}
+
+			if matrix.Criteria[j].IsCost() {
+				normalized[i][j] = (maxValues[j] - value) / (maxValues[j] - minValues[j])
+			} else {
+				normalized[i][j] = (value - minValues[j]) / (maxValues[j] - minValues[j])
+			}
+		}
+	}
+	return normalized
+}
+
+func (SMART) Aggregate(matrix Matrix, weighted [][]float64) []float64 {
+	scores := make([]float64, len(weighted))
+	for i, row := range weighted {
+		for _, value := range row {
+			scores[i] += value
+		}
+	}
+	return scores
+}
+
+func (SMART) TraceAggregation(matrix Matrix, weighted [][]float64) ([]map[string]float64, []map[string]float64) {
+	products := make([]map[string]float64, len(weighted))
+	for i, row := range weighted {
+		var total float64
+		for _, value := range row {
+			total += value
+		}
+		products[i] = map[string]float64{"sum_weighted": total}
+	}
+	return nil, products
+}
@@ -0,0 +1,92 @@
+package ranking
+
+import "math"
+
+func init() {
+	Register(TOPSIS{})
+}
+
+// TOPSIS menggunakan jarak terhadap solusi ideal positif dan negatif
+type TOPSIS struct {
+	weightedByCriteria
+}
+
+func (TOPSIS) Name() string {
+	return "TOPSIS"
+}
+
+func (TOPSIS) Parameters() map[string]string {
+	return map[string]string{
+		"normalization": "vektor",
+		"weighting":     "normalisasi x bobot",
+		"aggregation":   "Ci = D- / (D+ + D-)",
+	}
+}
+
+func (TOPSIS) Normalize(matrix Matrix) [][]float64 {
+	return vectorNormalize(matrix)
+}
+
+func (TOPSIS) Aggregate(matrix Matrix, weighted [][]float64) []float64 {
+	idealPositive, idealNegative := idealSolutions(matrix, weighted)
+
+	scores := make([]float64, len(weighted))
+	for i, row := range weighted {
+		var dPositive, dNegative float64
+		for j, value := range row {
+			dPositive += math.Pow(value-idealPositive[j], 2)
+			dNegative += math.Pow(value-idealNegative[j], 2)
+		}
+		dPositive = math.Sqrt(dPositive)
+		dNegative = math.Sqrt(dNegative)
+
+		if dPositive+dNegative != 0 {
+			scores[i] = dNegative / (dPositive + dNegative)
+		}
+	}
+	return scores
+}
+
+// idealSolutions menghitung solusi ideal positif (A+) dan negatif (A-) setiap kriteria
+func idealSolutions(matrix Matrix, weighted [][]float64) (positive []float64, negative []float64) {
+	positive = make([]float64, len(matrix.Criteria))
+	negative = make([]float64, len(matrix.Criteria))
+	for i, row := range weighted {
+		for j, value := range row {
+			if i == 0 {
+				positive[j], negative[j] = value, value
+				continue
+			}
+
+			// Untuk kriteria cost nilai terkecil adalah yang terbaik
+			if matrix.Criteria[j].IsCost() {
+				positive[j] = math.Min(positive[j], value)
+				negative[j] = math.Max(negative[j], value)
+			} else {
+				positive[j] = math.Max(positive[j], value)
+				negative[j] = math.Min(negative[j], value)
+			}
+		}
+	}
+	return positive, negative
+}
+
+func (TOPSIS) TraceAggregation(matrix Matrix, weighted [][]float64) ([]map[string]float64, []map[string]float64) {
+	idealPositive, idealNegative := idealSolutions(matrix, weighted)
+
+	criteria := make([]map[string]float64, len(matrix.Criteria))
+	for j := range matrix.Criteria {
+		criteria[j] = map[string]float64{"ideal_positive": idealPositive[j], "ideal_negative": idealNegative[j]}
+	}
+
+	products := make([]map[string]float64, len(weighted))
+	for i, row := range weighted {
+		var dPositive, dNegative float64
+		for j, value := range row {
+			dPositive += math.Pow(value-idealPositive[j], 2)
+			dNegative += math.Pow(value-idealNegative[j], 2)
+		}
+		products[i] = map[string]float64{"distance_positive": math.Sqrt(dPositive), "distance_negative": math.Sqrt(dNegative)}
+	}
+	return criteria, products
+}
@@ -0,0 +1,53 @@
+package ranking
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTOPSIS(t *testing.T) {
+	criteria := []Criterion{
+		{ID: 1, Name: "A", Weight: 0.5, Type: "Benefit"},
+		{ID: 2, Name: "B", Weight: 0.5, Type: "Cost"},
+	}
+
+	// norma kedua kolom = 5 sehingga nilai terbobot (3, 4) -> (0.3, 0.4) dan (4, 3) -> (0.4, 0.3)
+	tests := []struct {
+		name   string
+		values [][]float64
+		want   []float64
+	}{
+		{
+			name:   "dua produk pada solusi ideal",
+			values: [][]float64{{3, 4}, {4, 3}},
+			want:   []float64{0, 1},
+		},
+		{
+			// A+ = (0.4, 0), A- = (0, 0.4)
+			name:   "tiga produk",
+			values: [][]float64{{3, 4}, {4, 3}, {0, 0}},
+			want: []float64{
+				0.3 / (math.Sqrt(0.17) + 0.3),
+				math.Sqrt(0.17) / (0.3 + math.Sqrt(0.17)),
+				0.5,
+			},
+		},
+		{
+			name:   "semua produk sama",
+			values: [][]float64{{2, 2}, {2, 2}},
+			want:   []float64{0, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matrix := Matrix{ProductIDs: make([]int, len(test.values)), Criteria: criteria, Values: test.values}
+			scores := Run(TOPSIS{}, matrix).Scores
+			for i, want := range test.want {
+				if math.Abs(scores[i]-want) > 1e-9 {
+					t.Errorf("skor produk %d = %v, want %v", i, scores[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package ranking
+
+import "math"
+
+// AggregationTracer diimplementasikan algoritma yang dapat menjelaskan tahap agregasinya:
+// nilai tambahan per kriteria (mis. solusi ideal TOPSIS) dan rincian perhitungan skor per produk
+type AggregationTracer interface {
+	TraceAggregation(matrix Matrix, weighted [][]float64) (criteria []map[string]float64, products []map[string]float64)
+}
+
+type CriterionTrace struct {
+	ID     int                `json:"id"`
+	Name   string             `json:"name"`
+	Type   string             `json:"type"`
+	Weight float64            `json:"weight"`
+	Min    float64            `json:"min"`
+	Max    float64            `json:"max"`
+	Norm   float64            `json:"norm"`
+	Extra  map[string]float64 `json:"extra,omitempty"`
+}
+
+type ProductTrace struct {
+	ProductID   int                `json:"product_id"`
+	ProductName string             `json:"product_name"`
+	Details     map[string]float64 `json:"details,omitempty"`
+	Score       float64            `json:"score"`
+	Rank        int                `json:"rank"`
+}
+
+// Trace adalah seluruh tahap perhitungan, baris matriks mengikuti urutan Aggregation dan kolom mengikuti Criteria
+type Trace struct {
+	Algorithm      string            `json:"algorithm"`
+	Parameters     map[string]string `json:"parameters"`
+	Criteria       []CriterionTrace  `json:"criteria"`
+	DecisionMatrix [][]float64       `json:"decision_matrix"`
+	Normalized     [][]float64       `json:"normalized"`
+	Weighted       [][]float64       `json:"weighted"`
+	Aggregation    []ProductTrace    `json:"aggregation"`
+}
+
+func NewTrace(algorithm Algorithm, matrix Matrix, result Result) Trace {
+	trace := Trace{
+		Algorithm:      algorithm.Name(),
+		Parameters:     algorithm.Parameters(),
+		Criteria:       make([]CriterionTrace, len(matrix.Criteria)),
+		DecisionMatrix: matrix.Values,
+		Normalized:     result.Normalized,
+		Weighted:       result.Weighted,
+		Aggregation:    make([]ProductTrace, len(matrix.ProductIDs)),
+	}
+
+	for j, criterion := range matrix.Criteria {
+		trace.Criteria[j] = CriterionTrace{
+			ID:     criterion.ID,
+			Name:   criterion.Name,
+			Type:   criterion.Type,
+			Weight: criterion.Weight,
+		}
+
+		var sumSquares float64
+		for i, row := range matrix.Values {
+			if i == 0 || row[j] < trace.Criteria[j].Min {
+				trace.Criteria[j].Min = row[j]
+			}
+			if i == 0 || row[j] > trace.Criteria[j].Max {
+				trace.Criteria[j].Max = row[j]
+			}
+			sumSquares += row[j] * row[j]
+		}
+		trace.Criteria[j].Norm = math.Sqrt(sumSquares)
+	}
+
+	var criteriaDetails, productDetails []map[string]float64
+	if tracer, ok := algorithm.(AggregationTracer); ok {
+		criteriaDetails, productDetails = tracer.TraceAggregation(matrix, result.Weighted)
+	}
+	for j := range criteriaDetails {
+		trace.Criteria[j].Extra = criteriaDetails[j]
+	}
+
+	ranks := Ranks(result.Scores)
+	for i, productID := range matrix.ProductIDs {
+		trace.Aggregation[i] = ProductTrace{
+			ProductID: productID,
+			Score:     result.Scores[i],
+			Rank:      ranks[i],
+		}
+		if i < len(productDetails) {
+			trace.Aggregation[i].Details = productDetails[i]
+		}
+	}
+	return trace
+}
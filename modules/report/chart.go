@@ -0,0 +1,200 @@
+package report
+
+import (
+	"fmt"
+	"github.com/jung-kurt/gofpdf"
+	"math"
+	"strconv"
+)
+
+// trendSeries adalah garis peringkat satu produk, Ranks bernilai 0 jika produk tidak ada pada laporan tersebut
+type trendSeries struct {
+	Name  string
+	Ranks []int
+}
+
+var chartColors = [][3]int{
+	{31, 119, 180},
+	{255, 127, 14},
+	{44, 160, 44},
+	{214, 39, 40},
+	{148, 103, 189},
+	{140, 86, 75},
+	{227, 119, 194},
+	{127, 127, 127},
+}
+
+// drawRankChart menggambar grafik garis peringkat dengan primitif gofpdf pada area (x, y, w, h),
+// sumbu Y terbalik sehingga peringkat 1 berada di atas. Legenda digambar di bawah area grafik
+func drawRankChart(pdf *gofpdf.Fpdf, x, y, w, h float64, labels []string, series []trendSeries, maxRank int) {
+	if maxRank < 1 {
+		maxRank = 1
+	}
+
+	rankY := func(rank int) float64 {
+		if maxRank == 1 {
+			return y + h/2
+		}
+		return y + float64(rank-1)/float64(maxRank-1)*h
+	}
+	pointX := func(i int) float64 {
+		if len(labels) <= 1 {
+			return x + w/2
+		}
+		return x + float64(i)*w/float64(len(labels)-1)
+	}
+
+	// garis bantu dan label sumbu Y
+	pdf.SetFont("", "", 8)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetLineWidth(0.1)
+	pdf.SetDrawColor(200, 200, 200)
+	pdf.SetDashPattern([]float64{1, 1}, 0)
+	step := int(math.Ceil(float64(maxRank) / 10))
+	for rank := 1; rank <= maxRank; rank += step {
+		lineY := rankY(rank)
+		pdf.Line(x, lineY, x+w, lineY)
+		pdf.Text(x-6, lineY+1, strconv.Itoa(rank))
+	}
+	pdf.SetDashPattern([]float64{}, 0)
+
+	// sumbu
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(0.3)
+	pdf.Line(x, y-3, x, y+h+3)
+	pdf.Line(x, y+h+3, x+w, y+h+3)
+
+	// label sumbu X diputar agar kode laporan yang panjang tidak saling menimpa
+	for i, label := range labels {
+		labelX := pointX(i)
+		pdf.Line(labelX, y+h+3, labelX, y+h+4)
+		pdf.TransformBegin()
+		pdf.TransformRotate(-45, labelX, y+h+7)
+		pdf.Text(labelX, y+h+7, label)
+		pdf.TransformEnd()
+	}
+
+	pdf.SetLineWidth(0.6)
+	for s, line := range series {
+		color := chartColors[s%len(chartColors)]
+		pdf.SetDrawColor(color[0], color[1], color[2])
+		pdf.SetFillColor(color[0], color[1], color[2])
+
+		for i, rank := range line.Ranks {
+			if rank == 0 {
+				continue
+			}
+			if i > 0 && line.Ranks[i-1] != 0 {
+				pdf.Line(pointX(i-1), rankY(line.Ranks[i-1]), pointX(i), rankY(rank))
+			}
+			pdf.Circle(pointX(i), rankY(rank), 0.9, "F")
+		}
+	}
+
+	// legenda
+	legendY := y + h + 30
+	for s, line := range series {
+		color := chartColors[s%len(chartColors)]
+		legendX := x + float64(s%3)*(w/3)
+		rowY := legendY + float64(s/3)*6
+		pdf.SetFillColor(color[0], color[1], color[2])
+		pdf.Rect(legendX, rowY-2.5, 4, 3, "F")
+		pdf.Text(legendX+6, rowY, line.Name)
+	}
+
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetFillColor(255, 255, 255)
+	pdf.SetLineWidth(0.2)
+}
+
+// drawScoreBars menggambar grafik batang horizontal skor akhir pada area selebar w mulai dari (x, y),
+// skor negatif (mis. MOORA) digambar ke kiri dari garis nol. Mengembalikan posisi Y setelah grafik
+func drawScoreBars(pdf *gofpdf.Fpdf, x, y, w float64, names []string, scores []float64, color [3]int) float64 {
+	const labelWidth, barHeight, gap = 45.0, 5.0, 1.5
+
+	low, high := 0.0, 0.0
+	for _, score := range scores {
+		low = math.Min(low, score)
+		high = math.Max(high, score)
+	}
+	if high == low {
+		high = low + 1
+	}
+
+	chartX := x + labelWidth
+	chartWidth := w - labelWidth - 20
+	scale := chartWidth / (high - low)
+	zeroX := chartX - low*scale
+
+	pdf.SetFont("", "", 8)
+	pdf.SetTextColor(0, 0, 0)
+	for i, score := range scores {
+		rowY := y + float64(i)*(barHeight+gap)
+		pdf.SetXY(x, rowY)
+		pdf.CellFormat(labelWidth-2, barHeight, names[i], "", 0, "R", false, 0, "")
+
+		barX := math.Min(zeroX, zeroX+score*scale)
+		pdf.SetFillColor(color[0], color[1], color[2])
+		pdf.Rect(barX, rowY, math.Abs(score*scale), barHeight, "F")
+
+		labelX := math.Max(zeroX, zeroX+score*scale) + 1
+		pdf.Text(labelX, rowY+barHeight-1, fmt.Sprintf("%.4f", score))
+	}
+
+	bottom := y + float64(len(scores))*(barHeight+gap)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(0.3)
+	pdf.Line(zeroX, y-1, zeroX, bottom)
+	pdf.SetFillColor(255, 255, 255)
+	pdf.SetLineWidth(0.2)
+	return bottom
+}
+
+// drawWeightPie menggambar diagram lingkaran bobot kriteria berpusat di (centerX, centerY) dengan legenda
+// di sebelah kanan. Gofpdf tidak memiliki juring, setiap juring digambar sebagai poligon
+func drawWeightPie(pdf *gofpdf.Fpdf, centerX, centerY, radius float64, names []string, weights []float64) {
+	var total float64
+	for _, weight := range weights {
+		total += math.Max(weight, 0)
+	}
+	if total == 0 {
+		return
+	}
+
+	start := -90.0
+	pdf.SetDrawColor(255, 255, 255)
+	pdf.SetLineWidth(0.4)
+	for i, weight := range weights {
+		sweep := math.Max(weight, 0) / total * 360
+		if sweep == 0 {
+			continue
+		}
+
+		points := []gofpdf.PointType{{X: centerX, Y: centerY}}
+		steps := int(math.Ceil(sweep / 2))
+		for s := 0; s <= steps; s++ {
+			angle := (start + sweep*float64(s)/float64(steps)) * math.Pi / 180
+			points = append(points, gofpdf.PointType{X: centerX + radius*math.Cos(angle), Y: centerY + radius*math.Sin(angle)})
+		}
+
+		color := chartColors[i%len(chartColors)]
+		pdf.SetFillColor(color[0], color[1], color[2])
+		pdf.Polygon(points, "FD")
+		start += sweep
+	}
+
+	pdf.SetFont("", "", 9)
+	pdf.SetTextColor(0, 0, 0)
+	legendX := centerX + radius + 15
+	for i, name := range names {
+		color := chartColors[i%len(chartColors)]
+		rowY := centerY - radius + float64(i)*7
+		pdf.SetFillColor(color[0], color[1], color[2])
+		pdf.Rect(legendX, rowY-3, 4, 4, "F")
+		pdf.Text(legendX+6, rowY, fmt.Sprintf("%s (%.1f%%)", name, math.Max(weights[i], 0)/total*100))
+	}
+
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetFillColor(255, 255, 255)
+	pdf.SetLineWidth(0.2)
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/method"
+	"encoding/csv"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+	"net/http"
+	"strconv"
+)
+
+// reportHeaders adalah kolom tabel laporan Excel dan CSV, PDF memakai kolom sesuai template laporan (pdfColumns)
+var reportHeaders = []string{"Rank", "Nama Produk", "Skor Akhir", "Harga Beli", "Harga Jual", "Keuntungan", "Satuan", "Stok", "Stok Terjual"}
+
+func reportRow(index int, detail ReportDetail) []interface{} {
+	return []interface{}{
+		index + 1,
+		detail.Product.Name,
+		detail.FinalScore,
+		detail.Product.PurchaseCost,
+		detail.Product.PriceSale,
+		detail.Product.Profit,
+		detail.Product.Unit,
+		detail.Product.Stock,
+		detail.Product.Sold,
+	}
+}
+
+// exportExcel menulis tabel laporan pada sheet "Laporan" dan informasi laporan, metode serta bobot
+// kriteria yang dipakai saat perhitungan pada sheet "Informasi"
+func (service *reportService) exportExcel(ctx *gin.Context, getReport Report, getMethod method.Method, details []ReportDetail) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	style, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold: true,
+		},
+	})
+	if err != nil {
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membuat style"})
+		return
+	}
+
+	// format rupiah untuk kolom harga beli, harga jual dan keuntungan
+	currencyFormat := `"Rp" #,##0`
+	currencyStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &currencyFormat})
+	if err != nil {
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membuat style"})
+		return
+	}
+
+	// Sheet tabel laporan
+	sheet := "Laporan"
+	f.SetSheetName("Sheet1", sheet)
+	for i, header := range reportHeaders {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, header)
+		f.SetCellStyle(sheet, cell, cell, style)
+	}
+	for i, detail := range details {
+		for j, value := range reportRow(i, detail) {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+2)
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+	if len(details) > 0 {
+		f.SetCellStyle(sheet, "D2", fmt.Sprintf("F%d", len(details)+1), currencyStyle)
+	}
+	f.SetColWidth(sheet, "B", "B", 30)
+	f.SetColWidth(sheet, "C", "F", 15)
+
+	// Sheet informasi laporan dan kriteria
+	infoSheet := "Informasi"
+	f.NewSheet(infoSheet)
+	periodName := getReport.PeriodName
+	if periodName == "" {
+		periodName = "-"
+	}
+	information := [][]interface{}{
+		{"Kode Laporan", getReport.ReportCode},
+		{"Metode", getMethod.Name},
+		{"Algoritma", getMethod.Algorithm},
+		{"Periode", periodName},
+		{"Jumlah Data", getReport.TotalData},
+		{"ID Riwayat Perhitungan", getReport.CalculationRunID},
+		{"Dibuat Pada", getReport.CreatedAt.Format("02-01-2006 15:04:05")},
+	}
+	for i, row := range information {
+		f.SetCellValue(infoSheet, fmt.Sprintf("A%d", i+1), row[0])
+		f.SetCellValue(infoSheet, fmt.Sprintf("B%d", i+1), row[1])
+		f.SetCellStyle(infoSheet, fmt.Sprintf("A%d", i+1), fmt.Sprintf("A%d", i+1), style)
+	}
+
+	criteriaRow := len(information) + 2
+	for i, header := range []string{"Kriteria", "Tipe", "Bobot"} {
+		cell, _ := excelize.CoordinatesToCellName(i+1, criteriaRow)
+		f.SetCellValue(infoSheet, cell, header)
+		f.SetCellStyle(infoSheet, cell, cell, style)
+	}
+	for i, item := range getReport.Criteria {
+		row := criteriaRow + i + 1
+		f.SetCellValue(infoSheet, fmt.Sprintf("A%d", row), item.Name)
+		f.SetCellValue(infoSheet, fmt.Sprintf("B%d", row), item.Type)
+		f.SetCellValue(infoSheet, fmt.Sprintf("C%d", row), item.Weight)
+	}
+	f.SetColWidth(infoSheet, "A", "B", 25)
+
+	fileName := fmt.Sprintf("laporan-%s.xlsx", getReport.ReportCode)
+
+	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+
+	if err := f.Write(ctx.Writer); err != nil {
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membuat file Excel"})
+		return
+	}
+}
+
+// exportCSV hanya berisi tabel laporan karena CSV tidak memiliki sheet, angka ditulis tanpa format rupiah
+func (service *reportService) exportCSV(ctx *gin.Context, getReport Report, details []ReportDetail) {
+	fileName := fmt.Sprintf("laporan-%s.csv", getReport.ReportCode)
+
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+
+	writer := csv.NewWriter(ctx.Writer)
+	records := [][]string{reportHeaders}
+	for i, detail := range details {
+		records = append(records, []string{
+			strconv.Itoa(i + 1),
+			detail.Product.Name,
+			strconv.FormatFloat(detail.FinalScore, 'f', -1, 64),
+			strconv.Itoa(detail.Product.PurchaseCost),
+			strconv.Itoa(detail.Product.PriceSale),
+			strconv.Itoa(detail.Product.Profit),
+			detail.Product.Unit,
+			strconv.Itoa(detail.Product.Stock),
+			strconv.Itoa(detail.Product.Sold),
+		})
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membuat file CSV"})
+		return
+	}
+}
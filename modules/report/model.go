@@ -1,19 +1,24 @@
 package report
 
 import (
+	"backend-profitrack/modules/criteria"
 	"backend-profitrack/modules/method"
-	"backend-profitrack/modules/product"
+	"gorm.io/gorm"
 	"time"
 )
 
 type Report struct {
-	ID         int           `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
-	MethodID   int           `gorm:"integer;not null" json:"method_id"`
-	ReportCode string        `gorm:"varchar(50);not null" json:"report_code"`
-	TotalData  int           `gorm:"double" json:"total_data"`
-	Method     method.Method `gorm:"foreignkey:MethodID" json:"-"`
-	CreatedAt  time.Time     `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt  time.Time     `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID               int               `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	MethodID         int               `gorm:"integer;not null" json:"method_id"`
+	CalculationRunID int               `gorm:"integer;index" json:"calculation_run_id"`
+	PeriodID         int               `gorm:"integer;not null;default:0;index" json:"period_id"`
+	PeriodName       string            `gorm:"varchar(50)" json:"period_name"`
+	ReportCode       string            `gorm:"varchar(50);not null" json:"report_code"`
+	TotalData        int               `gorm:"double" json:"total_data"`
+	Criteria         criteria.Snapshot `gorm:"type:jsonb" json:"criteria"`
+	Method           method.Method     `gorm:"foreignkey:MethodID" json:"-"`
+	CreatedAt        time.Time         `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time         `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
 type ReportDetail struct {
@@ -22,9 +27,97 @@ type ReportDetail struct {
 	ProductID  int             `gorm:"integer;not null" json:"product_id"`
 	ReportID   int             `gorm:"integer;not null" json:"report_id"`
 	FinalScore float64         `gorm:"double" json:"final_scores"`
+	Product    ProductSnapshot `gorm:"embedded;embeddedPrefix:product_" json:"product"`
 	Method     method.Method   `gorm:"foreignkey:MethodID" json:"-"`
-	Product    product.Product `gorm:"foreignkey:ProductID" json:"product"`
 	Report     Report          `gorm:"foreignkey:ReportID" json:"-"`
 	CreatedAt  time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt  time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
+
+// ProductSnapshot adalah data produk yang dipakai saat perhitungan, disalin ketika laporan dibuat
+// agar perubahan atau penghapusan produk tidak mengubah isi laporan
+type ProductSnapshot struct {
+	ID           int    `gorm:"-" json:"id"`
+	Name         string `gorm:"varchar(50)" json:"name"`
+	PurchaseCost int    `gorm:"type:integer" json:"purchase_cost"`
+	PriceSale    int    `gorm:"type:integer" json:"price_sale"`
+	Profit       int    `gorm:"type:integer" json:"profit"`
+	Unit         string `gorm:"varchar(25)" json:"unit"`
+	Stock        int    `gorm:"type:integer" json:"stock"`
+	Sold         int    `gorm:"type:integer" json:"sold"`
+}
+
+func (detail *ReportDetail) AfterFind(tx *gorm.DB) (err error) {
+	detail.Product.ID = detail.ProductID
+	return nil
+}
+
+// ProductRank adalah skor akhir dan peringkat sebuah produk pada satu laporan
+type ProductRank struct {
+	ReportID    int       `json:"report_id"`
+	ReportCode  string    `json:"report_code"`
+	MethodID    int       `json:"method_id"`
+	PeriodID    int       `json:"period_id"`
+	PeriodName  string    `json:"period_name"`
+	ProductID   int       `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	FinalScore  float64   `json:"final_score"`
+	ProductRank int       `json:"rank"`
+	TotalData   int       `json:"total_data"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ResponseTrendPoint.RankChange positif berarti peringkat naik dibanding laporan sebelumnya pada metode yang sama
+type ResponseTrendPoint struct {
+	ReportID   int       `json:"report_id"`
+	ReportCode string    `json:"report_code"`
+	PeriodName string    `json:"period_name"`
+	FinalScore float64   `json:"final_score"`
+	Rank       int       `json:"rank"`
+	TotalData  int       `json:"total_data"`
+	RankChange int       `json:"rank_change"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ResponseMethodTrend struct {
+	MethodID   int                  `json:"method_id"`
+	MethodName string               `json:"method_name"`
+	BestRank   int                  `json:"best_rank"`
+	WorstRank  int                  `json:"worst_rank"`
+	Points     []ResponseTrendPoint `json:"points"`
+}
+
+type ResponseProductTrend struct {
+	ProductID   int                   `json:"product_id"`
+	ProductName string                `json:"product_name"`
+	Methods     []ResponseMethodTrend `json:"methods"`
+}
+
+type ResponseReportReference struct {
+	ID         int       `json:"id"`
+	ReportCode string    `json:"report_code"`
+	MethodID   int       `json:"method_id"`
+	PeriodName string    `json:"period_name"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ResponseMover.Change = peringkat awal - peringkat akhir, positif berarti naik. FromRank atau ToRank
+// bernilai 0 jika produk tidak ada pada laporan tersebut
+type ResponseMover struct {
+	ProductID   int     `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	FromRank    int     `json:"from_rank"`
+	ToRank      int     `json:"to_rank"`
+	Change      int     `json:"change"`
+	FromScore   float64 `json:"from_score"`
+	ToScore     float64 `json:"to_score"`
+}
+
+type ResponseMovers struct {
+	From    ResponseReportReference `json:"from"`
+	To      ResponseReportReference `json:"to"`
+	Risers  []ResponseMover         `json:"risers"`
+	Fallers []ResponseMover         `json:"fallers"`
+	Entered []ResponseMover         `json:"entered"`
+	Exited  []ResponseMover         `json:"exited"`
+}
@@ -1,6 +1,9 @@
 package report
 
-import "gorm.io/gorm"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 type Repository interface {
 	CountReportsRepository() (total int64, err error)
@@ -9,6 +12,8 @@ type Repository interface {
 	GetAllReportDetailRepository(ID int) (result []ReportDetail, err error)
 	DeleteReportRepository(report *Report) (err error)
 	DeleteDetailReportRepository(reportID int) (err error)
+	GetProductRanksRepository(productID int) (result []ProductRank, err error)
+	GetMethodRanksRepository(methodID int, until time.Time, productIDs []int) (result []ProductRank, err error)
 }
 
 type reportRepository struct {
@@ -35,7 +40,7 @@ func (r *reportRepository) GetReportByIDRepository(ID int) (result Report, err e
 }
 
 func (r *reportRepository) GetAllReportDetailRepository(ID int) (result []ReportDetail, err error) {
-	err = r.DB.Preload("Product").Where("report_id = ?", ID).Find(&result).Error
+	err = r.DB.Where("report_id = ?", ID).Order("final_score DESC, id ASC").Find(&result).Error
 	return result, err
 }
 
@@ -48,3 +53,34 @@ func (r *reportRepository) DeleteDetailReportRepository(reportID int) (err error
 	err = r.DB.Where("report_id = ?", reportID).Delete(&ReportDetail{}).Error
 	return err
 }
+
+// rankedDetails adalah detail laporan beserta peringkat produk di dalam laporannya, urutannya sama dengan
+// urutan pada PDF (skor akhir tertinggi lebih dulu)
+func (r *reportRepository) rankedDetails() *gorm.DB {
+	ranked := r.DB.Table("report_details").
+		Select("report_id, product_id, product_name, final_score, ROW_NUMBER() OVER (PARTITION BY report_id ORDER BY final_score DESC, id ASC) AS product_rank")
+
+	return r.DB.Table("(?) AS ranked", ranked).
+		Select("ranked.report_id, reports.report_code, reports.method_id, reports.period_id, reports.period_name, ranked.product_id, " +
+			"ranked.product_name, ranked.final_score, ranked.product_rank, reports.total_data, reports.created_at").
+		Joins("JOIN reports ON reports.id = ranked.report_id")
+}
+
+// GetProductRanksRepository mengambil peringkat produk pada setiap laporan, diurutkan per metode lalu waktu laporan
+func (r *reportRepository) GetProductRanksRepository(productID int) (result []ProductRank, err error) {
+	err = r.rankedDetails().
+		Where("ranked.product_id = ?", productID).
+		Order("reports.method_id ASC, reports.created_at ASC, reports.id ASC").
+		Scan(&result).Error
+	return result, err
+}
+
+// GetMethodRanksRepository mengambil peringkat produk productIDs pada laporan metode methodID yang dibuat
+// sampai waktu until, diurutkan berdasarkan waktu laporan
+func (r *reportRepository) GetMethodRanksRepository(methodID int, until time.Time, productIDs []int) (result []ProductRank, err error) {
+	err = r.rankedDetails().
+		Where("reports.method_id = ? AND reports.created_at <= ? AND ranked.product_id IN ?", methodID, until, productIDs).
+		Order("reports.created_at ASC, reports.id ASC").
+		Scan(&result).Error
+	return result, err
+}
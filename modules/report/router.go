@@ -2,7 +2,9 @@ package report
 
 import (
 	"backend-profitrack/middleware"
+	"backend-profitrack/modules/criteria"
 	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/report_template"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -10,13 +12,17 @@ import (
 func Initiator(router *gin.Engine, db *gorm.DB) {
 	repo := NewReportRepository(db)
 	methodRepo := method.NewMethodRepository(db)
-	service := NewReportService(repo, methodRepo)
+	criteriaRepo := criteria.NewCriteriaRepository(db)
+	templateRepo := report_template.NewReportTemplateRepository(db)
+	service := NewReportService(repo, methodRepo, criteriaRepo, templateRepo)
 
 	api := router.Group("/api")
 	api.Use(middleware.LoggingMiddleware())
 	api.Use(middleware.JWTMiddleware())
 	api.GET("/reports", service.GetAllReportsService)
 	api.GET("/reports/count", service.CountReportsService)
+	api.GET("/reports/movers", service.GetMoversService)
+	api.GET("/reports/trend/products/:productID", service.GetProductTrendService)
 	api.GET("/reports/:id", service.GetDetailReportService)
 	api.GET("/reports/export/:id", service.ExportPDFService)
 	api.DELETE("/reports/:id", service.DeleteDetailReportService, service.DeleteAllReportService)
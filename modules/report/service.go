@@ -2,14 +2,18 @@ package report
 
 import (
 	"backend-profitrack/helpers"
+	"backend-profitrack/modules/criteria"
 	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/report_template"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/jung-kurt/gofpdf"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -20,15 +24,22 @@ type Service interface {
 	ExportPDFService(ctx *gin.Context)
 	DeleteDetailReportService(ctx *gin.Context)
 	DeleteAllReportService(ctx *gin.Context)
+	GetProductTrendService(ctx *gin.Context)
+	GetMoversService(ctx *gin.Context)
 }
 
+// chartProducts adalah jumlah produk teratas yang ditampilkan pada grafik skor akhir
+const chartProducts = 20
+
 type reportService struct {
-	repository       Repository
-	methodRepository method.Repository
+	repository         Repository
+	methodRepository   method.Repository
+	criteriaRepository criteria.Repository
+	templateRepository report_template.Repository
 }
 
-func NewReportService(repo Repository, methodRepo method.Repository) Service {
-	return &reportService{repository: repo, methodRepository: methodRepo}
+func NewReportService(repo Repository, methodRepo method.Repository, criteriaRepo criteria.Repository, templateRepo report_template.Repository) Service {
+	return &reportService{repository: repo, methodRepository: methodRepo, criteriaRepository: criteriaRepo, templateRepository: templateRepo}
 }
 
 func (service *reportService) CountReportsService(ctx *gin.Context) {
@@ -86,6 +97,12 @@ func (service *reportService) GetDetailReportService(ctx *gin.Context) {
 	helpers.ResponseJSON(ctx, http.StatusOK, reports)
 }
 
+// trendProducts adalah jumlah produk teratas yang digambar pada halaman grafik tren PDF
+const trendProducts = 5
+
+// ExportPDFService mengunduh laporan, query format: pdf (default), xlsx atau csv. Untuk PDF, query
+// trend=true menambahkan halaman grafik tren peringkat produk teratas pada laporan-laporan metode yang sama
+// dan template_id memilih template laporan (tanpa template_id memakai template default)
 func (service *reportService) ExportPDFService(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
@@ -94,6 +111,13 @@ func (service *reportService) ExportPDFService(ctx *gin.Context) {
 		return
 	}
 
+	format := strings.ToLower(ctx.DefaultQuery("format", "pdf"))
+	if format != "pdf" && format != "xlsx" && format != "csv" {
+		response := map[string]string{"error": "format harus pdf, xlsx atau csv"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
 	reports, err := service.repository.GetAllReportDetailRepository(id)
 	if err != nil {
 		response := map[string]string{"error": "gagal mendapatkan detail laporan"}
@@ -101,6 +125,12 @@ func (service *reportService) ExportPDFService(ctx *gin.Context) {
 		return
 	}
 
+	if len(reports) == 0 {
+		response := map[string]string{"error": "data laporan masih kosong"}
+		helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+		return
+	}
+
 	getMethod, err := service.methodRepository.GetMethodByIdRepository(reports[0].MethodID)
 	if err != nil {
 		response := map[string]string{"error": "gagal mendapatkan nama metode"}
@@ -109,81 +139,76 @@ func (service *reportService) ExportPDFService(ctx *gin.Context) {
 	}
 
 	methodName := getMethod.Name
+	getReport, _ := service.repository.GetReportByIDRepository(id)
 
-	// Create a new PDF document
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-
-	// Set margins (left, top, right)
-	pdf.SetMargins(15, 15, 15)
+	switch format {
+	case "xlsx":
+		service.exportExcel(ctx, getReport, getMethod, reports)
+		return
+	case "csv":
+		service.exportCSV(ctx, getReport, reports)
+		return
+	}
 
-	// Add title
-	pdf.SetFont("Times", "B", 16)
-	pdf.CellFormat(0, 10, fmt.Sprintf("Laporan Hasil Perhitungan %s", methodName), "", 1, "C", false, 0, "")
-	pdf.Ln(10)
+	template, statusCode, err := report_template.Resolve(service.templateRepository, ctx.Query("template_id"))
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+	layout := newPDFLayout(template)
 
-	// Add descriptive paragraph
-	pdf.SetFont("Times", "", 12)
-	description := fmt.Sprintf(
-		"Laporan ini menyajikan hasil perhitungan menggunakan sistem pendukung keputusan (SPK) dengan metode %s. "+
-			"Penilaian didasarkan pada kriteria kinerja produk dari perspektif keuangan, termasuk Return On Investment, Net Profit Margin, dan Rasio Efisiensi. "+
-			"Berikut ini adalah nilai akhir, peringkat, serta detail data masing-masing produk",
-		methodName,
-	)
-	pdf.MultiCell(0, 8, description, "", "L", false)
-	pdf.Ln(10)
+	// laporan periode mencantumkan nama periodenya pada judul
+	title := fmt.Sprintf(layout.text.TitleFormat, methodName)
+	if getReport.PeriodName != "" {
+		title = fmt.Sprintf(layout.text.PeriodFormat, title, getReport.PeriodName)
+	}
 
-	// Set headers with styling
-	pdf.SetFont("Times", "B", 10)
-	headers := []string{"Rank", "Nama Produk", "Skor Akhir", "Harga Beli", "Harga Jual", "Keuntungan", "Satuan", "Stok", "Stok Terjual"}
-	colWidths := []float64{10, 30, 20, 20, 20, 25, 15, 15, 20} // Column widths in mm
+	// Create a new PDF document sesuai orientasi, font, kop dan footer template
+	pdf := layout.newPDF()
+	pdf.AddPage()
 
-	// Add header background
-	pdf.SetFillColor(200, 200, 200) // Light gray background for header
+	// Add title
+	pdf.SetFont("", "B", 16)
+	pdf.CellFormat(0, 10, title, "", 1, "C", false, 0, "")
+	pdf.Ln(10)
 
-	// Print headers
-	for i, header := range headers {
-		pdf.CellFormat(colWidths[i], 10, header, "1", 0, "C", true, 0, "")
+	// Add descriptive paragraph, disusun dari kriteria yang dipakai saat perhitungan
+	snapshot, err := service.reportCriteria(getReport)
+	if err != nil {
+		response := map[string]string{"error": "gagal mendapatkan data kriteria"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
 	}
-	pdf.Ln(-1)
 
-	// Reset fill color and set regular font for data
-	pdf.SetFillColor(255, 255, 255)
-	pdf.SetFont("Times", "", 10)
+	pdf.SetFont("", "", 12)
+	pdf.MultiCell(0, 8, criteriaDescription(layout.text, methodName, snapshot), "", "L", false)
+	pdf.Ln(6)
 
-	// Add data rows
-	for i, report := range reports {
-		// Alternate row colors for better readability
-		fill := i%2 == 1
-		if fill {
-			pdf.SetFillColor(240, 240, 240) // Very light gray for alternating rows
-		} else {
-			pdf.SetFillColor(255, 255, 255) // White
-		}
+	layout.writeSummary(pdf, summarize(reports), len(reports))
 
-		// Format numbers with appropriate separators
-		purchaseCost := fmt.Sprintf(formatCurrency(float64(report.Product.PurchaseCost)))
-		priceSale := fmt.Sprintf(formatCurrency(float64(report.Product.PriceSale)))
-		profit := fmt.Sprintf(formatCurrency(float64(report.Product.Profit)))
+	layout.addChartPage(pdf, reports, snapshot)
 
-		// Print data row
-		pdf.CellFormat(colWidths[0], 8, fmt.Sprintf("%d", i+1), "1", 0, "C", fill, 0, "")
-		pdf.CellFormat(colWidths[1], 8, report.Product.Name, "1", 0, "L", fill, 0, "")
-		pdf.CellFormat(colWidths[2], 8, fmt.Sprintf("%f", report.FinalScore), "1", 0, "C", fill, 0, "")
-		pdf.CellFormat(colWidths[3], 8, purchaseCost, "1", 0, "R", fill, 0, "")
-		pdf.CellFormat(colWidths[4], 8, priceSale, "1", 0, "R", fill, 0, "")
-		pdf.CellFormat(colWidths[5], 8, profit, "1", 0, "R", fill, 0, "")
-		pdf.CellFormat(colWidths[6], 8, report.Product.Unit, "1", 0, "C", fill, 0, "")
-		pdf.CellFormat(colWidths[7], 8, fmt.Sprintf("%d", report.Product.Stock), "1", 0, "C", fill, 0, "")
-		pdf.CellFormat(colWidths[8], 8, fmt.Sprintf("%d", report.Product.Sold), "1", 0, "C", fill, 0, "")
-		pdf.Ln(-1)
-	}
+	// Add data rows dengan kolom sesuai template
+	layout.writeTable(pdf, reports)
 
 	// Add footer with date
 	pdf.Ln(10)
-	pdf.SetFont("Times", "I", 8)
+	pdf.SetFont("", "I", 8)
 	currentTime := time.Now()
-	pdf.CellFormat(0, 10, fmt.Sprintf("Laporan dibuat pada: %s", reports[0].CreatedAt.Format("02-01-2006 15:04:05")), "", 0, "R", false, 0, "")
+	pdf.CellFormat(0, 10, fmt.Sprintf(layout.text.CreatedAtFormat, reports[0].CreatedAt.Format("02-01-2006 15:04:05")), "", 1, "R", false, 0, "")
+
+	pdf.Ln(5)
+	layout.writeSignature(pdf)
+
+	if ctx.Query("trend") == "true" {
+		err = service.addTrendPage(pdf, layout, getReport, methodName, reports)
+		if err != nil {
+			response := map[string]string{"error": "gagal mengambil data tren peringkat"}
+			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+			return
+		}
+	}
 
 	// Set filename
 	fileName := fmt.Sprintf("data-produk-%s.pdf", currentTime.Format("02-01-2006"))
@@ -201,6 +226,69 @@ func (service *reportService) ExportPDFService(ctx *gin.Context) {
 	}
 }
 
+// reportCriteria mengembalikan kriteria yang tersimpan pada laporan, laporan lama yang belum menyimpan
+// kriteria memakai data kriteria saat ini
+func (service *reportService) reportCriteria(getReport Report) (criteria.Snapshot, error) {
+	if len(getReport.Criteria) > 0 {
+		return getReport.Criteria, nil
+	}
+
+	criteriaList, err := service.criteriaRepository.GetAllCriteriaRepository()
+	if err != nil {
+		return nil, err
+	}
+	return criteria.NewSnapshot(criteriaList), nil
+}
+
+// addChartPage menambahkan halaman grafik batang skor akhir dan diagram lingkaran bobot kriteria,
+// tabel detail dimulai pada halaman berikutnya
+func (layout pdfLayout) addChartPage(pdf *gofpdf.Fpdf, details []ReportDetail, snapshot criteria.Snapshot) {
+	pdf.AddPage()
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - left - right
+
+	count := min(chartProducts, len(details))
+	names := make([]string, count)
+	scores := make([]float64, count)
+	for i := 0; i < count; i++ {
+		names[i] = fmt.Sprintf("%d. %s", i+1, details[i].Product.Name)
+		scores[i] = details[i].FinalScore
+	}
+
+	pdf.SetFont("", "B", 13)
+	pdf.CellFormat(0, 8, layout.text.ScoreChart, "", 1, "L", false, 0, "")
+	if count < len(details) {
+		pdf.SetFont("", "I", 9)
+		pdf.CellFormat(0, 6, fmt.Sprintf(layout.text.ChartLimitFormat, count, len(details)), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+	bottom := drawScoreBars(pdf, left, pdf.GetY(), width, names, scores, layout.chartColor)
+
+	if len(snapshot) > 0 {
+		criteriaNames := make([]string, len(snapshot))
+		weights := make([]float64, len(snapshot))
+		for i, item := range snapshot {
+			criteriaNames[i] = item.Name
+			weights[i] = item.Weight
+		}
+
+		// pada halaman landscape diagram lingkaran dapat tidak muat di bawah grafik skor
+		radius := 30.0
+		_, pageHeight := pdf.GetPageSize()
+		_, _, _, bottomMargin := pdf.GetMargins()
+		pdf.SetXY(left, bottom+10)
+		if bottom+10+8+2*radius+5 > pageHeight-bottomMargin {
+			pdf.AddPage()
+		}
+		pdf.SetFont("", "B", 13)
+		pdf.CellFormat(0, 8, layout.text.WeightChart, "", 1, "L", false, 0, "")
+		drawWeightPie(pdf, left+radius+10, pdf.GetY()+radius+5, radius, criteriaNames, weights)
+	}
+
+	pdf.AddPage()
+}
+
 // Helper function to format currency
 func formatCurrency(amount float64) string {
 	p := message.NewPrinter(language.Indonesian)
@@ -260,3 +348,242 @@ func (service *reportService) DeleteAllReportService(ctx *gin.Context) {
 	response := map[string]string{"message": "berhasil menghapus laporan"}
 	helpers.ResponseJSON(ctx, http.StatusOK, response)
 }
+
+// addTrendPage menambahkan halaman grafik peringkat produk teratas laporan ini pada setiap laporan
+// metode yang sama sampai laporan ini dibuat
+func (service *reportService) addTrendPage(pdf *gofpdf.Fpdf, layout pdfLayout, getReport Report, methodName string, details []ReportDetail) error {
+	productIDs := make([]int, 0, trendProducts)
+	for i := 0; i < len(details) && i < trendProducts; i++ {
+		productIDs = append(productIDs, details[i].ProductID)
+	}
+
+	ranks, err := service.repository.GetMethodRanksRepository(getReport.MethodID, getReport.CreatedAt, productIDs)
+	if err != nil {
+		return err
+	}
+
+	// sumbu X adalah laporan sesuai urutan waktu, label memakai nama periode jika ada
+	var labels []string
+	columns := make(map[int]int)
+	maxRank := 1
+	for _, rank := range ranks {
+		if _, exists := columns[rank.ReportID]; !exists {
+			columns[rank.ReportID] = len(labels)
+			label := rank.CreatedAt.Format("02-01-06")
+			if rank.PeriodName != "" {
+				label = rank.PeriodName
+			}
+			labels = append(labels, label)
+		}
+		maxRank = max(maxRank, rank.ProductRank)
+	}
+
+	rows := make(map[int]int, len(productIDs))
+	series := make([]trendSeries, len(productIDs))
+	for i, productID := range productIDs {
+		rows[productID] = i
+		series[i] = trendSeries{Name: details[i].Product.Name, Ranks: make([]int, len(labels))}
+	}
+	for _, rank := range ranks {
+		series[rows[rank.ProductID]].Ranks[columns[rank.ReportID]] = rank.ProductRank
+	}
+
+	pdf.AddPage()
+	pdf.SetFont("", "B", 14)
+	pdf.CellFormat(0, 10, fmt.Sprintf(layout.text.TrendTitleFormat, len(productIDs)), "", 1, "C", false, 0, "")
+	pdf.SetFont("", "", 11)
+	pdf.MultiCell(0, 6, fmt.Sprintf(layout.text.TrendFormat, methodName, len(labels)), "", "L", false)
+
+	// grafik berada di tengah halaman, kop template dapat menggeser posisi awalnya
+	pageWidth, _ := pdf.GetPageSize()
+	drawRankChart(pdf, (pageWidth-150)/2, pdf.GetY()+10, 150, 100, labels, series, maxRank)
+	return nil
+}
+
+// GetProductTrendService menampilkan skor akhir dan peringkat sebuah produk pada setiap laporan,
+// dikelompokkan per metode dan diurutkan berdasarkan waktu laporan
+func (service *reportService) GetProductTrendService(ctx *gin.Context) {
+	productID, err := strconv.Atoi(ctx.Param("productID"))
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	ranks, err := service.repository.GetProductRanksRepository(productID)
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data tren peringkat"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(ranks) == 0 {
+		response := map[string]string{"error": fmt.Sprintf("produk ID:%d belum ada pada laporan manapun", productID)}
+		helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+		return
+	}
+
+	result := ResponseProductTrend{ProductID: productID}
+	var latest time.Time
+	for _, rank := range ranks {
+		// nama produk diambil dari salinan data produk pada laporan terbaru
+		if !rank.CreatedAt.Before(latest) {
+			latest = rank.CreatedAt
+			result.ProductName = rank.ProductName
+		}
+
+		last := len(result.Methods) - 1
+		if last < 0 || result.Methods[last].MethodID != rank.MethodID {
+			methodName := ""
+			if getMethod, err := service.methodRepository.GetMethodByIdRepository(rank.MethodID); err == nil {
+				methodName = getMethod.Name
+			}
+			result.Methods = append(result.Methods, ResponseMethodTrend{
+				MethodID:   rank.MethodID,
+				MethodName: methodName,
+				BestRank:   rank.ProductRank,
+				WorstRank:  rank.ProductRank,
+			})
+			last++
+		}
+
+		trend := &result.Methods[last]
+		point := ResponseTrendPoint{
+			ReportID:   rank.ReportID,
+			ReportCode: rank.ReportCode,
+			PeriodName: rank.PeriodName,
+			FinalScore: rank.FinalScore,
+			Rank:       rank.ProductRank,
+			TotalData:  rank.TotalData,
+			CreatedAt:  rank.CreatedAt,
+		}
+		if len(trend.Points) > 0 {
+			point.RankChange = trend.Points[len(trend.Points)-1].Rank - rank.ProductRank
+		}
+		trend.BestRank = min(trend.BestRank, rank.ProductRank)
+		trend.WorstRank = max(trend.WorstRank, rank.ProductRank)
+		trend.Points = append(trend.Points, point)
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+// GetMoversService membandingkan dua laporan metode yang sama (query from dan to berisi ID laporan) dan
+// menampilkan produk dengan kenaikan dan penurunan peringkat terbesar, query opsional limit (default 5)
+func (service *reportService) GetMoversService(ctx *gin.Context) {
+	fromID, errFrom := strconv.Atoi(ctx.Query("from"))
+	toID, errTo := strconv.Atoi(ctx.Query("to"))
+	if errFrom != nil || errTo != nil || fromID == toID {
+		response := map[string]string{"error": "from dan to harus berisi dua ID laporan yang berbeda"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "5"))
+	if err != nil || limit < 1 {
+		response := map[string]string{"error": "limit harus lebih dari 0"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	var references [2]ResponseReportReference
+	var details [2][]ReportDetail
+	for i, reportID := range []int{fromID, toID} {
+		getReport, _ := service.repository.GetReportByIDRepository(reportID)
+		if getReport.ID == 0 {
+			response := map[string]string{"error": fmt.Sprintf("Laporan dengan ID:%d tidak ditemukan", reportID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+
+		details[i], err = service.repository.GetAllReportDetailRepository(reportID)
+		if err != nil {
+			response := map[string]string{"error": "gagal mendapatkan detail laporan"}
+			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+			return
+		}
+
+		references[i] = ResponseReportReference{
+			ID:         getReport.ID,
+			ReportCode: getReport.ReportCode,
+			MethodID:   getReport.MethodID,
+			PeriodName: getReport.PeriodName,
+			CreatedAt:  getReport.CreatedAt,
+		}
+	}
+
+	// peringkat dari metode berbeda tidak dapat dibandingkan
+	if references[0].MethodID != references[1].MethodID {
+		response := map[string]string{"error": fmt.Sprintf("laporan ID:%d dan ID:%d dibuat dengan metode yang berbeda", fromID, toID)}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	result := ResponseMovers{
+		From:    references[0],
+		To:      references[1],
+		Risers:  []ResponseMover{},
+		Fallers: []ResponseMover{},
+		Entered: []ResponseMover{},
+		Exited:  []ResponseMover{},
+	}
+
+	// detail laporan sudah terurut berdasarkan skor akhir, posisi = peringkat
+	fromRanks := make(map[int]int, len(details[0]))
+	for position, detail := range details[0] {
+		fromRanks[detail.ProductID] = position + 1
+	}
+
+	toProducts := make(map[int]bool, len(details[1]))
+	for position, detail := range details[1] {
+		toProducts[detail.ProductID] = true
+		mover := ResponseMover{
+			ProductID:   detail.ProductID,
+			ProductName: detail.Product.Name,
+			ToRank:      position + 1,
+			ToScore:     detail.FinalScore,
+		}
+
+		fromRank, exists := fromRanks[detail.ProductID]
+		if !exists {
+			result.Entered = append(result.Entered, mover)
+			continue
+		}
+
+		mover.FromRank = fromRank
+		mover.FromScore = details[0][fromRank-1].FinalScore
+		mover.Change = fromRank - mover.ToRank
+		switch {
+		case mover.Change > 0:
+			result.Risers = append(result.Risers, mover)
+		case mover.Change < 0:
+			result.Fallers = append(result.Fallers, mover)
+		}
+	}
+
+	for position, detail := range details[0] {
+		if !toProducts[detail.ProductID] {
+			result.Exited = append(result.Exited, ResponseMover{
+				ProductID:   detail.ProductID,
+				ProductName: detail.Product.Name,
+				FromRank:    position + 1,
+				FromScore:   detail.FinalScore,
+			})
+		}
+	}
+
+	sort.SliceStable(result.Risers, func(i, j int) bool {
+		return result.Risers[i].Change > result.Risers[j].Change
+	})
+	sort.SliceStable(result.Fallers, func(i, j int) bool {
+		return result.Fallers[i].Change < result.Fallers[j].Change
+	})
+	if len(result.Risers) > limit {
+		result.Risers = result.Risers[:limit]
+	}
+	if len(result.Fallers) > limit {
+		result.Fallers = result.Fallers[:limit]
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
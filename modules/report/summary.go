@@ -0,0 +1,195 @@
+package report
+
+import (
+	"backend-profitrack/modules/criteria"
+	"fmt"
+	"github.com/jung-kurt/gofpdf"
+	"math"
+	"sort"
+	"strings"
+)
+
+// summaryProducts adalah jumlah produk teratas dan terbawah pada ringkasan eksekutif
+const summaryProducts = 5
+
+// summaryBins adalah jumlah rentang skor pada tabel distribusi skor
+const summaryBins = 5
+
+type scoreBin struct {
+	From  float64
+	To    float64
+	Count int
+}
+
+// reportSummary adalah ringkasan eksekutif laporan, AverageMargin adalah rata-rata
+// keuntungan / harga jual (persen) produk dengan harga jual lebih dari 0
+type reportSummary struct {
+	Top           []ReportDetail
+	Bottom        []ReportDetail
+	BottomFrom    int
+	Min           float64
+	Max           float64
+	Mean          float64
+	Median        float64
+	StdDev        float64
+	Bins          []scoreBin
+	AverageMargin float64
+}
+
+// summarize menghitung ringkasan dari detail laporan yang sudah terurut berdasarkan skor akhir tertinggi
+func summarize(details []ReportDetail) reportSummary {
+	var summary reportSummary
+	if len(details) == 0 {
+		return summary
+	}
+
+	// produk terbawah tidak mengulang produk teratas, kosong jika jumlah produk tidak lebih dari summaryProducts
+	count := min(summaryProducts, len(details))
+	bottomFrom := max(count, len(details)-summaryProducts)
+	summary.Top = details[:count]
+	summary.Bottom = details[bottomFrom:]
+	summary.BottomFrom = bottomFrom + 1
+
+	scores := make([]float64, len(details))
+	var total, marginTotal float64
+	marginCount := 0
+	for i, detail := range details {
+		scores[i] = detail.FinalScore
+		total += detail.FinalScore
+		if detail.Product.PriceSale > 0 {
+			marginTotal += float64(detail.Product.Profit) / float64(detail.Product.PriceSale) * 100
+			marginCount++
+		}
+	}
+	sort.Float64s(scores)
+
+	summary.Min = scores[0]
+	summary.Max = scores[len(scores)-1]
+	summary.Mean = total / float64(len(scores))
+	if len(scores)%2 == 1 {
+		summary.Median = scores[len(scores)/2]
+	} else {
+		summary.Median = (scores[len(scores)/2-1] + scores[len(scores)/2]) / 2
+	}
+
+	var variance float64
+	for _, score := range scores {
+		variance += (score - summary.Mean) * (score - summary.Mean)
+	}
+	summary.StdDev = math.Sqrt(variance / float64(len(scores)))
+
+	if marginCount > 0 {
+		summary.AverageMargin = marginTotal / float64(marginCount)
+	}
+
+	// rentang skor dengan lebar yang sama antara skor terendah dan tertinggi
+	bins := summaryBins
+	if summary.Max == summary.Min {
+		bins = 1
+	}
+	width := (summary.Max - summary.Min) / float64(bins)
+	for b := 0; b < bins; b++ {
+		summary.Bins = append(summary.Bins, scoreBin{
+			From: summary.Min + float64(b)*width,
+			To:   summary.Min + float64(b+1)*width,
+		})
+	}
+	for _, score := range scores {
+		b := bins - 1
+		if width > 0 {
+			b = min(int((score-summary.Min)/width), bins-1)
+		}
+		summary.Bins[b].Count++
+	}
+
+	return summary
+}
+
+// criteriaDescription menyusun paragraf pembuka laporan dari kriteria yang dipakai saat perhitungan
+func criteriaDescription(text pdfText, methodName string, snapshot criteria.Snapshot) string {
+	description := fmt.Sprintf(text.IntroFormat, methodName)
+	if len(snapshot) == 0 {
+		return description + text.NoCriteria
+	}
+
+	items := make([]string, 0, len(snapshot))
+	for _, item := range snapshot {
+		items = append(items, fmt.Sprintf(text.CriteriaItemFormat, item.Name, strings.ToLower(item.Type), item.Weight*100))
+	}
+
+	list := items[0]
+	if len(items) > 1 {
+		list = strings.Join(items[:len(items)-1], ", ") + text.And + items[len(items)-1]
+	}
+
+	return description + fmt.Sprintf(text.CriteriaFormat, len(snapshot), list)
+}
+
+// writeSummary menulis bagian ringkasan eksekutif: produk teratas dan terbawah, statistik dan distribusi skor
+func (layout pdfLayout) writeSummary(pdf *gofpdf.Fpdf, summary reportSummary, total int) {
+	text := layout.text
+	pdf.SetFont("", "B", 13)
+	pdf.CellFormat(0, 8, text.Summary, "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	// produk teratas dan terbawah berdampingan, tabel terbawah tidak ditampilkan jika kosong
+	left, _, _, _ := pdf.GetMargins()
+	pdf.SetFont("", "B", 10)
+	layout.setHeaderFill(pdf)
+	if len(summary.Bottom) == 0 {
+		pdf.CellFormat(85, 7, fmt.Sprintf(text.TopFormat, len(summary.Top)), "1", 1, "C", true, 0, "")
+	} else {
+		pdf.CellFormat(85, 7, fmt.Sprintf(text.TopFormat, len(summary.Top)), "1", 0, "C", true, 0, "")
+		pdf.CellFormat(10, 7, "", "", 0, "C", false, 0, "")
+		pdf.CellFormat(85, 7, fmt.Sprintf(text.BottomFormat, len(summary.Bottom)), "1", 1, "C", true, 0, "")
+	}
+
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("", "", 10)
+	for i := range summary.Top {
+		// baris tanpa pasangan produk terbawah langsung pindah baris setelah kolom skor
+		withBottom := i < len(summary.Bottom)
+		ln := 1
+		if withBottom {
+			ln = 0
+		}
+
+		pdf.SetX(left)
+		pdf.CellFormat(10, 7, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(50, 7, summary.Top[i].Product.Name, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(25, 7, fmt.Sprintf("%.4f", summary.Top[i].FinalScore), "1", ln, "C", false, 0, "")
+		if !withBottom {
+			continue
+		}
+
+		pdf.CellFormat(10, 7, "", "", 0, "C", false, 0, "")
+		pdf.CellFormat(10, 7, fmt.Sprintf("%d", summary.BottomFrom+i), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(50, 7, summary.Bottom[i].Product.Name, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(25, 7, fmt.Sprintf("%.4f", summary.Bottom[i].FinalScore), "1", 1, "C", false, 0, "")
+	}
+	pdf.Ln(5)
+
+	// statistik skor
+	pdf.SetFont("", "", 11)
+	pdf.MultiCell(0, 6, fmt.Sprintf(
+		text.StatisticsFormat,
+		total, summary.Min, summary.Max, summary.Mean, summary.Median, summary.StdDev, summary.AverageMargin,
+	), "", "L", false)
+	pdf.Ln(3)
+
+	// distribusi skor
+	pdf.SetFont("", "B", 10)
+	layout.setHeaderFill(pdf)
+	pdf.CellFormat(90, 7, text.ScoreRange, "1", 0, "C", true, 0, "")
+	pdf.CellFormat(40, 7, text.ProductCount, "1", 0, "C", true, 0, "")
+	pdf.CellFormat(40, 7, text.Percentage, "1", 1, "C", true, 0, "")
+
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("", "", 10)
+	for _, bin := range summary.Bins {
+		pdf.CellFormat(90, 7, fmt.Sprintf("%.4f - %.4f", bin.From, bin.To), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(40, 7, fmt.Sprintf("%d", bin.Count), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(40, 7, fmt.Sprintf("%.1f%%", float64(bin.Count)/float64(total)*100), "1", 1, "C", false, 0, "")
+	}
+	pdf.SetFillColor(255, 255, 255)
+}
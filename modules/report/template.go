@@ -0,0 +1,348 @@
+package report
+
+import (
+	"backend-profitrack/modules/report_template"
+	"bytes"
+	"fmt"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// pdfText adalah teks PDF laporan dalam satu bahasa, field berakhiran "Format" dipakai dengan fmt.Sprintf
+type pdfText struct {
+	TitleFormat        string
+	PeriodFormat       string
+	IntroFormat        string
+	NoCriteria         string
+	CriteriaFormat     string
+	CriteriaItemFormat string
+	And                string
+	Summary            string
+	TopFormat          string
+	BottomFormat       string
+	StatisticsFormat   string
+	ScoreRange         string
+	ProductCount       string
+	Percentage         string
+	ScoreChart         string
+	ChartLimitFormat   string
+	WeightChart        string
+	TrendTitleFormat   string
+	TrendFormat        string
+	CreatedAtFormat    string
+	PageFormat         string
+	Acknowledged       string
+	Columns            map[string]string
+}
+
+var pdfTexts = map[string]pdfText{
+	report_template.LanguageIndonesian: {
+		TitleFormat:        "Laporan Hasil Perhitungan %s",
+		PeriodFormat:       "%s Periode %s",
+		IntroFormat:        "Laporan ini menyajikan hasil perhitungan menggunakan sistem pendukung keputusan (SPK) dengan metode %s. ",
+		NoCriteria:         "Berikut ini adalah nilai akhir, peringkat, serta detail data masing-masing produk.",
+		CriteriaItemFormat: "%s (%s, bobot %.1f%%)",
+		And:                " dan ",
+		CriteriaFormat: "Penilaian didasarkan pada %d kriteria, yaitu %s. Kriteria benefit semakin baik jika nilainya semakin tinggi, " +
+			"sedangkan kriteria cost semakin baik jika nilainya semakin rendah. " +
+			"Berikut ini adalah ringkasan, grafik, nilai akhir, peringkat, serta detail data masing-masing produk.",
+		Summary:      "Ringkasan Eksekutif",
+		TopFormat:    "%d Produk Teratas",
+		BottomFormat: "%d Produk Terbawah",
+		StatisticsFormat: "Dari %d produk, skor akhir berada antara %.4f dan %.4f dengan rata-rata %.4f, median %.4f dan simpangan baku %.4f. " +
+			"Rata-rata margin keuntungan (keuntungan / harga jual) seluruh produk adalah %.2f%%.",
+		ScoreRange:       "Rentang Skor Akhir",
+		ProductCount:     "Jumlah Produk",
+		Percentage:       "Persentase",
+		ScoreChart:       "Grafik Skor Akhir",
+		ChartLimitFormat: "Menampilkan %d dari %d produk dengan skor akhir tertinggi",
+		WeightChart:      "Bobot Kriteria",
+		TrendTitleFormat: "Tren Peringkat %d Produk Teratas",
+		TrendFormat: "Grafik berikut menunjukkan peringkat produk teratas pada laporan ini di setiap laporan metode %s " +
+			"sampai laporan ini dibuat (%d laporan). Peringkat 1 berada di bagian atas grafik.",
+		CreatedAtFormat: "Laporan dibuat pada: %s",
+		PageFormat:      "Halaman %d",
+		Acknowledged:    "Mengetahui,",
+		Columns: map[string]string{
+			report_template.ColumnRank:         "Rank",
+			report_template.ColumnName:         "Nama Produk",
+			report_template.ColumnFinalScore:   "Skor Akhir",
+			report_template.ColumnPurchaseCost: "Harga Beli",
+			report_template.ColumnPriceSale:    "Harga Jual",
+			report_template.ColumnProfit:       "Keuntungan",
+			report_template.ColumnUnit:         "Satuan",
+			report_template.ColumnStock:        "Stok",
+			report_template.ColumnSold:         "Stok Terjual",
+		},
+	},
+	report_template.LanguageEnglish: {
+		TitleFormat:        "%s Calculation Report",
+		PeriodFormat:       "%s for Period %s",
+		IntroFormat:        "This report presents the results of a decision support system (DSS) calculation using the %s method. ",
+		NoCriteria:         "The final score, rank and details of each product are listed below.",
+		CriteriaItemFormat: "%s (%s, weight %.1f%%)",
+		And:                " and ",
+		CriteriaFormat: "The assessment is based on %d criteria: %s. A benefit criterion is better when its value is higher, " +
+			"while a cost criterion is better when its value is lower. " +
+			"The summary, charts, final score, rank and details of each product are listed below.",
+		Summary:      "Executive Summary",
+		TopFormat:    "Top %d Products",
+		BottomFormat: "Bottom %d Products",
+		StatisticsFormat: "Across %d products, final scores range from %.4f to %.4f with a mean of %.4f, a median of %.4f and a standard deviation of %.4f. " +
+			"The average profit margin (profit / sale price) of all products is %.2f%%.",
+		ScoreRange:       "Final Score Range",
+		ProductCount:     "Products",
+		Percentage:       "Percentage",
+		ScoreChart:       "Final Scores",
+		ChartLimitFormat: "Showing %d of %d products with the highest final score",
+		WeightChart:      "Criteria Weights",
+		TrendTitleFormat: "Rank Trend of the Top %d Products",
+		TrendFormat: "The chart below shows the rank of this report's top products in every %s report " +
+			"up to this report (%d reports). Rank 1 is at the top of the chart.",
+		CreatedAtFormat: "Report generated on: %s",
+		PageFormat:      "Page %d",
+		Acknowledged:    "Acknowledged by,",
+		Columns: map[string]string{
+			report_template.ColumnRank:         "Rank",
+			report_template.ColumnName:         "Product Name",
+			report_template.ColumnFinalScore:   "Final Score",
+			report_template.ColumnPurchaseCost: "Purchase Cost",
+			report_template.ColumnPriceSale:    "Sale Price",
+			report_template.ColumnProfit:       "Profit",
+			report_template.ColumnUnit:         "Unit",
+			report_template.ColumnStock:        "Stock",
+			report_template.ColumnSold:         "Sold",
+		},
+	},
+}
+
+// pdfColumn adalah lebar bawaan (mm, halaman potrait) dan perataan kolom tabel PDF, lebar disesuaikan
+// dengan lebar halaman sesuai kolom yang dipilih template
+type pdfColumn struct {
+	Width float64
+	Align string
+	Value func(index int, detail ReportDetail) string
+}
+
+var pdfColumns = map[string]pdfColumn{
+	report_template.ColumnRank: {10, "C", func(index int, detail ReportDetail) string {
+		return fmt.Sprintf("%d", index+1)
+	}},
+	report_template.ColumnName: {30, "L", func(index int, detail ReportDetail) string {
+		return detail.Product.Name
+	}},
+	report_template.ColumnFinalScore: {20, "C", func(index int, detail ReportDetail) string {
+		return fmt.Sprintf("%f", detail.FinalScore)
+	}},
+	report_template.ColumnPurchaseCost: {20, "R", func(index int, detail ReportDetail) string {
+		return formatCurrency(float64(detail.Product.PurchaseCost))
+	}},
+	report_template.ColumnPriceSale: {20, "R", func(index int, detail ReportDetail) string {
+		return formatCurrency(float64(detail.Product.PriceSale))
+	}},
+	report_template.ColumnProfit: {25, "R", func(index int, detail ReportDetail) string {
+		return formatCurrency(float64(detail.Product.Profit))
+	}},
+	report_template.ColumnUnit: {15, "C", func(index int, detail ReportDetail) string {
+		return detail.Product.Unit
+	}},
+	report_template.ColumnStock: {15, "C", func(index int, detail ReportDetail) string {
+		return fmt.Sprintf("%d", detail.Product.Stock)
+	}},
+	report_template.ColumnSold: {20, "C", func(index int, detail ReportDetail) string {
+		return fmt.Sprintf("%d", detail.Product.Sold)
+	}},
+}
+
+// pdfLayout adalah template laporan yang sudah diurai untuk dipakai saat menggambar PDF
+type pdfLayout struct {
+	template     report_template.ReportTemplate
+	text         pdfText
+	primaryColor [3]int
+	chartColor   [3]int
+}
+
+// newPDFLayout mengurai warna dan bahasa template, nilai yang tidak valid (mis. dari data lama) memakai nilai bawaan
+func newPDFLayout(template report_template.ReportTemplate) pdfLayout {
+	defaults := report_template.Default()
+	layout := pdfLayout{template: template}
+
+	text, exists := pdfTexts[template.Language]
+	if !exists {
+		text = pdfTexts[defaults.Language]
+	}
+	layout.text = text
+
+	var err error
+	if layout.primaryColor, err = report_template.ParseColor(template.PrimaryColor); err != nil {
+		layout.primaryColor, _ = report_template.ParseColor(defaults.PrimaryColor)
+	}
+	if layout.chartColor, err = report_template.ParseColor(template.ChartColor); err != nil {
+		layout.chartColor, _ = report_template.ParseColor(defaults.ChartColor)
+	}
+
+	if len(layout.template.Columns) == 0 {
+		layout.template.Columns = defaults.Columns
+	}
+	if layout.template.Orientation != report_template.OrientationLandscape {
+		layout.template.Orientation = report_template.OrientationPortrait
+	}
+	if layout.template.Font == "" {
+		layout.template.Font = defaults.Font
+	}
+	return layout
+}
+
+// newPDF membuat dokumen sesuai orientasi dan font template, kop (logo, nama perusahaan, teks header)
+// dan footer (teks footer, nomor halaman) digambar pada setiap halaman
+func (layout pdfLayout) newPDF() *gofpdf.Fpdf {
+	pdf := gofpdf.New(layout.template.Orientation, "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+
+	// halaman lain memanggil SetFont dengan family kosong sehingga font template tetap dipakai
+	pdf.SetFont(layout.template.Font, "", 12)
+
+	logo := ""
+	if len(layout.template.Logo) > 0 {
+		logo = "logo"
+		options := gofpdf.ImageOptions{ImageType: layout.template.LogoType, ReadDpi: false}
+		pdf.RegisterImageOptionsReader(logo, options, bytes.NewReader(layout.template.Logo))
+		if pdf.Err() {
+			// logo yang tidak dapat dibaca dilewati agar laporan tetap dapat dibuat
+			pdf.ClearError()
+			logo = ""
+		}
+	}
+
+	hasHeader := logo != "" || layout.template.CompanyName != "" || layout.template.HeaderText != ""
+	pdf.SetHeaderFunc(func() {
+		if !hasHeader {
+			return
+		}
+
+		left, top, right, _ := pdf.GetMargins()
+		pageWidth, _ := pdf.GetPageSize()
+		textX := left
+		if logo != "" {
+			pdf.ImageOptions(logo, left, top, 0, 15, false, gofpdf.ImageOptions{}, 0, "")
+			textX = left + 25
+		}
+
+		pdf.SetXY(textX, top)
+		pdf.SetFont("", "B", 14)
+		pdf.CellFormat(pageWidth-right-textX, 7, layout.template.CompanyName, "", 2, "L", false, 0, "")
+		pdf.SetFont("", "", 9)
+		pdf.MultiCell(pageWidth-right-textX, 4, layout.template.HeaderText, "", "L", false)
+
+		lineY := max(pdf.GetY(), top+15) + 2
+		pdf.SetDrawColor(layout.primaryColor[0], layout.primaryColor[1], layout.primaryColor[2])
+		pdf.SetLineWidth(0.6)
+		pdf.Line(left, lineY, pageWidth-right, lineY)
+		pdf.SetDrawColor(0, 0, 0)
+		pdf.SetLineWidth(0.2)
+		pdf.SetXY(left, lineY+5)
+	})
+
+	pdf.SetFooterFunc(func() {
+		left, _, _, _ := pdf.GetMargins()
+		pdf.SetY(-12)
+		pdf.SetFont("", "I", 8)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(0, 5, layout.template.FooterText, "", 0, "L", false, 0, "")
+		pdf.SetX(left)
+		pdf.CellFormat(0, 5, fmt.Sprintf(layout.text.PageFormat, pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	return pdf
+}
+
+// setHeaderFill memakai warna utama template sebagai latar header tabel, teks putih untuk warna gelap
+func (layout pdfLayout) setHeaderFill(pdf *gofpdf.Fpdf) {
+	r, g, b := layout.primaryColor[0], layout.primaryColor[1], layout.primaryColor[2]
+	pdf.SetFillColor(r, g, b)
+	if 299*r+587*g+114*b < 128000 {
+		pdf.SetTextColor(255, 255, 255)
+	} else {
+		pdf.SetTextColor(0, 0, 0)
+	}
+}
+
+// writeTable menulis tabel detail laporan dengan kolom dan urutan sesuai template
+func (layout pdfLayout) writeTable(pdf *gofpdf.Fpdf, details []ReportDetail) {
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+
+	columns := make([]pdfColumn, 0, len(layout.template.Columns))
+	headers := make([]string, 0, len(layout.template.Columns))
+	var total float64
+	for _, key := range layout.template.Columns {
+		column, exists := pdfColumns[key]
+		if !exists {
+			continue
+		}
+		columns = append(columns, column)
+		headers = append(headers, layout.text.Columns[key])
+		total += column.Width
+	}
+	scale := (pageWidth - left - right) / total
+
+	writeHeader := func() {
+		pdf.SetFont("", "B", 10)
+		layout.setHeaderFill(pdf)
+		for i, header := range headers {
+			pdf.CellFormat(columns[i].Width*scale, 10, header, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFont("", "", 10)
+	}
+	writeHeader()
+
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	for i, detail := range details {
+		// header tabel diulang pada setiap halaman
+		if pdf.GetY()+8 > pageHeight-bottom {
+			pdf.AddPage()
+			writeHeader()
+		}
+
+		// Alternate row colors for better readability
+		fill := i%2 == 1
+		if fill {
+			pdf.SetFillColor(240, 240, 240) // Very light gray for alternating rows
+		} else {
+			pdf.SetFillColor(255, 255, 255) // White
+		}
+
+		for _, column := range columns {
+			pdf.CellFormat(column.Width*scale, 8, column.Value(i, detail), "1", 0, column.Align, fill, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.SetFillColor(255, 255, 255)
+}
+
+// writeSignature menulis blok tanda tangan di sisi kanan jika template memiliki nama penanda tangan
+func (layout pdfLayout) writeSignature(pdf *gofpdf.Fpdf) {
+	if layout.template.SignatureName == "" {
+		return
+	}
+
+	const width, height = 70.0, 40.0
+	_, _, right, bottom := pdf.GetMargins()
+	pageWidth, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+height > pageHeight-bottom {
+		pdf.AddPage()
+	}
+
+	x := pageWidth - right - width
+	pdf.SetFont("", "", 11)
+	pdf.SetX(x)
+	pdf.CellFormat(width, 6, layout.text.Acknowledged, "", 2, "C", false, 0, "")
+	pdf.CellFormat(width, 6, layout.template.SignatureTitle, "", 2, "C", false, 0, "")
+	pdf.Ln(18)
+	pdf.SetX(x)
+	pdf.SetFont("", "BU", 11)
+	pdf.CellFormat(width, 6, layout.template.SignatureName, "", 1, "C", false, 0, "")
+}
@@ -0,0 +1,229 @@
+package report_template
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Orientasi halaman, bahasa dan font PDF yang didukung (font bawaan gofpdf)
+const (
+	OrientationPortrait  = "P"
+	OrientationLandscape = "L"
+
+	LanguageIndonesian = "id"
+	LanguageEnglish    = "en"
+)
+
+var Fonts = []string{"Times", "Helvetica", "Courier"}
+
+// Kolom tabel laporan yang dapat dipilih, DefaultColumns adalah seluruh kolom dengan urutan bawaan
+const (
+	ColumnRank         = "rank"
+	ColumnName         = "name"
+	ColumnFinalScore   = "final_score"
+	ColumnPurchaseCost = "purchase_cost"
+	ColumnPriceSale    = "price_sale"
+	ColumnProfit       = "profit"
+	ColumnUnit         = "unit"
+	ColumnStock        = "stock"
+	ColumnSold         = "sold"
+)
+
+var DefaultColumns = Columns{ColumnRank, ColumnName, ColumnFinalScore, ColumnPurchaseCost, ColumnPriceSale, ColumnProfit, ColumnUnit, ColumnStock, ColumnSold}
+
+// MaxLogoSize adalah ukuran maksimal logo yang diunggah (1 MB)
+const MaxLogoSize = 1 << 20
+
+// ReportTemplate adalah tampilan PDF laporan: kop (logo, nama perusahaan, teks header), footer, blok tanda tangan,
+// kolom tabel, orientasi, bahasa, font dan warna. Logo disimpan di database agar tersedia pada setiap instance server
+type ReportTemplate struct {
+	ID             int       `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	Name           string    `gorm:"type:varchar(50);UNIQUE;not null" json:"name"`
+	CompanyName    string    `gorm:"type:varchar(100)" json:"company_name"`
+	HeaderText     string    `gorm:"type:varchar(255)" json:"header_text"`
+	FooterText     string    `gorm:"type:varchar(255)" json:"footer_text"`
+	SignatureName  string    `gorm:"type:varchar(100)" json:"signature_name"`
+	SignatureTitle string    `gorm:"type:varchar(100)" json:"signature_title"`
+	Logo           []byte    `gorm:"type:bytea" json:"-"`
+	LogoType       string    `gorm:"type:varchar(10)" json:"-"`
+	Columns        Columns   `gorm:"type:jsonb;not null" json:"columns"`
+	Orientation    string    `gorm:"type:varchar(1);not null;default:'P'" json:"orientation"`
+	Language       string    `gorm:"type:varchar(2);not null;default:'id'" json:"language"`
+	Font           string    `gorm:"type:varchar(20);not null;default:'Times'" json:"font"`
+	PrimaryColor   string    `gorm:"type:varchar(7);not null;default:'#C8C8C8'" json:"primary_color"`
+	ChartColor     string    `gorm:"type:varchar(7);not null;default:'#1F77B4'" json:"chart_color"`
+	IsDefault      bool      `gorm:"not null;default:false" json:"is_default"`
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// RequestReportTemplate: field tampilan yang kosong diisi nilai bawaan (semua kolom, potrait, bahasa Indonesia,
+// font Times, header abu-abu). Warna berformat #RRGGBB
+type RequestReportTemplate struct {
+	Name           string   `json:"name"`
+	CompanyName    string   `json:"company_name"`
+	HeaderText     string   `json:"header_text"`
+	FooterText     string   `json:"footer_text"`
+	SignatureName  string   `json:"signature_name"`
+	SignatureTitle string   `json:"signature_title"`
+	Columns        []string `json:"columns"`
+	Orientation    string   `json:"orientation"`
+	Language       string   `json:"language"`
+	Font           string   `json:"font"`
+	PrimaryColor   string   `json:"primary_color"`
+	ChartColor     string   `json:"chart_color"`
+}
+
+type ResponseReportTemplate struct {
+	ReportTemplate
+	HasLogo bool `json:"has_logo"`
+}
+
+// Columns disimpan sebagai kolom jsonb
+type Columns []string
+
+func (c Columns) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+
+	value, err := json.Marshal(c)
+	return string(value), err
+}
+
+func (c *Columns) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, c)
+	case string:
+		return json.Unmarshal([]byte(data), c)
+	default:
+		return fmt.Errorf("tipe data kolom template tidak didukung: %T", value)
+	}
+}
+
+// Default adalah tampilan bawaan yang dipakai jika belum ada template default
+func Default() ReportTemplate {
+	return ReportTemplate{
+		Columns:      DefaultColumns,
+		Orientation:  OrientationPortrait,
+		Language:     LanguageIndonesian,
+		Font:         Fonts[0],
+		PrimaryColor: "#C8C8C8",
+		ChartColor:   "#1F77B4",
+	}
+}
+
+// Apply memvalidasi request lalu menyalinnya ke template, field tampilan yang kosong diisi nilai bawaan
+func (request RequestReportTemplate) Apply(template *ReportTemplate) error {
+	defaults := Default()
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return errors.New("nama template harus diisi")
+	}
+
+	columns := Columns(request.Columns)
+	if len(columns) == 0 {
+		columns = defaults.Columns
+	}
+	seen := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if !contains(DefaultColumns, column) {
+			return fmt.Errorf("kolom %s tidak dikenal, pilihan: %s", column, strings.Join(DefaultColumns, ", "))
+		}
+		if seen[column] {
+			return fmt.Errorf("kolom %s dipilih lebih dari sekali", column)
+		}
+		seen[column] = true
+	}
+
+	orientation := strings.ToUpper(valueOr(request.Orientation, defaults.Orientation))
+	if orientation != OrientationPortrait && orientation != OrientationLandscape {
+		return errors.New("orientation harus P (potrait) atau L (landscape)")
+	}
+
+	language := strings.ToLower(valueOr(request.Language, defaults.Language))
+	if language != LanguageIndonesian && language != LanguageEnglish {
+		return errors.New("language harus id atau en")
+	}
+
+	font := valueOr(request.Font, defaults.Font)
+	matched := false
+	for _, name := range Fonts {
+		if strings.EqualFold(name, font) {
+			font, matched = name, true
+		}
+	}
+	if !matched {
+		return fmt.Errorf("font harus salah satu dari: %s", strings.Join(Fonts, ", "))
+	}
+
+	primaryColor := strings.ToUpper(valueOr(request.PrimaryColor, defaults.PrimaryColor))
+	chartColor := strings.ToUpper(valueOr(request.ChartColor, defaults.ChartColor))
+	for _, color := range []string{primaryColor, chartColor} {
+		if _, err := ParseColor(color); err != nil {
+			return err
+		}
+	}
+
+	template.Name = request.Name
+	template.CompanyName = strings.TrimSpace(request.CompanyName)
+	template.HeaderText = strings.TrimSpace(request.HeaderText)
+	template.FooterText = strings.TrimSpace(request.FooterText)
+	template.SignatureName = strings.TrimSpace(request.SignatureName)
+	template.SignatureTitle = strings.TrimSpace(request.SignatureTitle)
+	template.Columns = columns
+	template.Orientation = orientation
+	template.Language = language
+	template.Font = font
+	template.PrimaryColor = primaryColor
+	template.ChartColor = chartColor
+	return nil
+}
+
+// ParseColor mengubah warna #RRGGBB menjadi komponen RGB
+func ParseColor(color string) ([3]int, error) {
+	var rgb [3]int
+	if len(color) != 7 || color[0] != '#' {
+		return rgb, fmt.Errorf("warna %s harus berformat #RRGGBB", color)
+	}
+
+	for i := range rgb {
+		value, err := strconv.ParseUint(color[1+i*2:3+i*2], 16, 8)
+		if err != nil {
+			return rgb, fmt.Errorf("warna %s harus berformat #RRGGBB", color)
+		}
+		rgb[i] = int(value)
+	}
+	return rgb, nil
+}
+
+func toResponseReportTemplate(template ReportTemplate) ResponseReportTemplate {
+	return ResponseReportTemplate{ReportTemplate: template, HasLogo: len(template.Logo) > 0}
+}
+
+func valueOr(value string, fallback string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
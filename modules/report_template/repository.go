@@ -0,0 +1,78 @@
+package report_template
+
+import (
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	GetAllReportTemplateRepository() (result []ReportTemplate, err error)
+	GetReportTemplateByIdRepository(templateID int) (result ReportTemplate, err error)
+	GetDefaultReportTemplateRepository() (result ReportTemplate, err error)
+	CreateReportTemplateRepository(template *ReportTemplate) (err error)
+	UpdateReportTemplateRepository(template *ReportTemplate) (err error)
+	DeleteReportTemplateRepository(template *ReportTemplate) (err error)
+	UpdateLogoRepository(templateID int, logo []byte, logoType string) (err error)
+	SetDefaultReportTemplateRepository(templateID int) (err error)
+}
+
+type reportTemplateRepository struct {
+	DB *gorm.DB
+}
+
+func NewReportTemplateRepository(db *gorm.DB) Repository {
+	return &reportTemplateRepository{
+		DB: db,
+	}
+}
+
+func (r *reportTemplateRepository) GetAllReportTemplateRepository() (result []ReportTemplate, err error) {
+	err = r.DB.Order("id ASC").Find(&result).Error
+	return result, err
+}
+
+func (r *reportTemplateRepository) GetReportTemplateByIdRepository(templateID int) (result ReportTemplate, err error) {
+	err = r.DB.First(&result, templateID).Error
+	return result, err
+}
+
+// GetDefaultReportTemplateRepository mengembalikan gorm.ErrRecordNotFound jika belum ada template default
+func (r *reportTemplateRepository) GetDefaultReportTemplateRepository() (result ReportTemplate, err error) {
+	err = r.DB.Where("is_default = ?", true).First(&result).Error
+	return result, err
+}
+
+func (r *reportTemplateRepository) CreateReportTemplateRepository(template *ReportTemplate) (err error) {
+	err = r.DB.Create(template).Error
+	return err
+}
+
+// UpdateReportTemplateRepository tidak mengubah logo dan status default, keduanya diubah lewat endpoint tersendiri
+func (r *reportTemplateRepository) UpdateReportTemplateRepository(template *ReportTemplate) (err error) {
+	err = r.DB.Model(template).Select("*").Omit("id", "logo", "logo_type", "is_default", "created_at").Updates(template).Error
+	return err
+}
+
+func (r *reportTemplateRepository) DeleteReportTemplateRepository(template *ReportTemplate) (err error) {
+	err = r.DB.Delete(template).Error
+	return err
+}
+
+// UpdateLogoRepository menyimpan logo template, logo nil menghapus logo
+func (r *reportTemplateRepository) UpdateLogoRepository(templateID int, logo []byte, logoType string) (err error) {
+	err = r.DB.Model(&ReportTemplate{}).Where("id = ?", templateID).Updates(map[string]interface{}{
+		"logo":      logo,
+		"logo_type": logoType,
+	}).Error
+	return err
+}
+
+// SetDefaultReportTemplateRepository menjadikan template sebagai satu-satunya template default
+func (r *reportTemplateRepository) SetDefaultReportTemplateRepository(templateID int) (err error) {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err = tx.Model(&ReportTemplate{}).Where("is_default = ? AND id <> ?", true, templateID).Update("is_default", false).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&ReportTemplate{}).Where("id = ?", templateID).Update("is_default", true).Error
+	})
+}
@@ -0,0 +1,25 @@
+package report_template
+
+import (
+	"backend-profitrack/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	repo := NewReportTemplateRepository(db)
+	service := NewReportTemplateService(repo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.GET("/report_templates", service.GetAllReportTemplateService)
+	api.GET("/report_templates/:id", service.GetReportTemplateByIdService)
+	api.POST("/report_templates", service.CreateReportTemplateService)
+	api.PUT("/report_templates/:id", service.UpdateReportTemplateService)
+	api.DELETE("/report_templates/:id", service.DeleteReportTemplateService)
+	api.PUT("/report_templates/:id/default", service.SetDefaultReportTemplateService)
+	api.GET("/report_templates/:id/logo", service.GetLogoService)
+	api.POST("/report_templates/:id/logo", service.UploadLogoService)
+	api.DELETE("/report_templates/:id/logo", service.DeleteLogoService)
+}
@@ -0,0 +1,321 @@
+package report_template
+
+import (
+	"backend-profitrack/helpers"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Service interface {
+	GetAllReportTemplateService(ctx *gin.Context)
+	GetReportTemplateByIdService(ctx *gin.Context)
+	CreateReportTemplateService(ctx *gin.Context)
+	UpdateReportTemplateService(ctx *gin.Context)
+	DeleteReportTemplateService(ctx *gin.Context)
+	SetDefaultReportTemplateService(ctx *gin.Context)
+	UploadLogoService(ctx *gin.Context)
+	GetLogoService(ctx *gin.Context)
+	DeleteLogoService(ctx *gin.Context)
+}
+
+type reportTemplateService struct {
+	repository Repository
+}
+
+func NewReportTemplateService(repo Repository) Service {
+	return &reportTemplateService{
+		repository: repo,
+	}
+}
+
+// Resolve mengambil template dari nilai query template_id. Nilai kosong berarti template default,
+// atau tampilan bawaan (Default) jika belum ada template yang dijadikan default
+func Resolve(repo Repository, value string) (result ReportTemplate, statusCode int, err error) {
+	if value == "" {
+		result, err = repo.GetDefaultReportTemplateRepository()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Default(), http.StatusOK, nil
+		}
+		if err != nil {
+			return result, http.StatusInternalServerError, err
+		}
+		return result, http.StatusOK, nil
+	}
+
+	templateID, err := strconv.Atoi(value)
+	if err != nil {
+		return result, http.StatusBadRequest, errors.New("template_id tidak sesuai")
+	}
+
+	result, err = repo.GetReportTemplateByIdRepository(templateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return result, http.StatusNotFound, fmt.Errorf("Template laporan dengan ID:%d tidak ditemukan", templateID)
+		}
+		return result, http.StatusInternalServerError, err
+	}
+	return result, http.StatusOK, nil
+}
+
+func (service *reportTemplateService) GetAllReportTemplateService(ctx *gin.Context) {
+	templates, err := service.repository.GetAllReportTemplateRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data template laporan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(templates) == 0 {
+		response := map[string]string{"message": "data template laporan masih kosong"}
+		helpers.ResponseJSON(ctx, http.StatusOK, response)
+		return
+	}
+
+	result := make([]ResponseReportTemplate, 0, len(templates))
+	for _, template := range templates {
+		result = append(result, toResponseReportTemplate(template))
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+func (service *reportTemplateService) GetReportTemplateByIdService(ctx *gin.Context) {
+	template, statusCode, err := service.findTemplate(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, toResponseReportTemplate(template))
+}
+
+func (service *reportTemplateService) CreateReportTemplateService(ctx *gin.Context) {
+	var request RequestReportTemplate
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	newTemplate := ReportTemplate{
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := request.Apply(&newTemplate); err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	err := service.repository.CreateReportTemplateRepository(&newTemplate)
+	if err != nil {
+		statusCode, message := saveError(err)
+		response := map[string]string{"error": message}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusCreated, toResponseReportTemplate(newTemplate))
+}
+
+// UpdateReportTemplateService mengganti seluruh isi template, logo dan status default tidak berubah
+func (service *reportTemplateService) UpdateReportTemplateService(ctx *gin.Context) {
+	template, statusCode, err := service.findTemplate(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	var request RequestReportTemplate
+	if err = ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if err = request.Apply(&template); err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+	template.UpdatedAt = time.Now()
+
+	err = service.repository.UpdateReportTemplateRepository(&template)
+	if err != nil {
+		statusCode, message := saveError(err)
+		response := map[string]string{"error": message}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, toResponseReportTemplate(template))
+}
+
+// DeleteReportTemplateService menghapus template, jika template default yang dihapus laporan kembali
+// memakai tampilan bawaan sampai template lain dijadikan default
+func (service *reportTemplateService) DeleteReportTemplateService(ctx *gin.Context) {
+	template, statusCode, err := service.findTemplate(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	err = service.repository.DeleteReportTemplateRepository(&template)
+	if err != nil {
+		response := map[string]string{"error": "gagal menghapus template laporan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := map[string]string{"message": fmt.Sprintf("Template laporan %s berhasil dihapus", template.Name)}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+// SetDefaultReportTemplateService menjadikan template sebagai template yang dipakai saat ekspor PDF tanpa template_id
+func (service *reportTemplateService) SetDefaultReportTemplateService(ctx *gin.Context) {
+	template, statusCode, err := service.findTemplate(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	err = service.repository.SetDefaultReportTemplateRepository(template.ID)
+	if err != nil {
+		response := map[string]string{"error": "gagal mengubah template default"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := map[string]string{"message": fmt.Sprintf("Template laporan %s dijadikan default", template.Name)}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+// UploadLogoService menyimpan logo dari field "logo" (PNG atau JPEG, maksimal 1 MB), logo lama diganti
+func (service *reportTemplateService) UploadLogoService(ctx *gin.Context) {
+	template, statusCode, err := service.findTemplate(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	file, err := ctx.FormFile("logo")
+	if err != nil {
+		response := map[string]string{"error": "File logo tidak ditemukan"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if file.Size > MaxLogoSize {
+		response := map[string]string{"error": "Ukuran logo maksimal 1 MB"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	opened, err := file.Open()
+	if err != nil {
+		response := map[string]string{"error": "Gagal membaca file logo"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+	defer opened.Close()
+
+	logo, err := io.ReadAll(io.LimitReader(opened, MaxLogoSize+1))
+	if err != nil {
+		response := map[string]string{"error": "Gagal membaca file logo"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	// jenis gambar dibaca dari isi file, bukan dari ekstensinya
+	_, format, err := image.DecodeConfig(bytes.NewReader(logo))
+	if err != nil || len(logo) > MaxLogoSize {
+		response := map[string]string{"error": "Format logo harus PNG atau JPEG"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	err = service.repository.UpdateLogoRepository(template.ID, logo, strings.ToUpper(format))
+	if err != nil {
+		response := map[string]string{"error": "gagal menyimpan logo"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := map[string]string{"message": fmt.Sprintf("Logo template laporan %s berhasil disimpan", template.Name)}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+func (service *reportTemplateService) GetLogoService(ctx *gin.Context) {
+	template, statusCode, err := service.findTemplate(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	if len(template.Logo) == 0 {
+		response := map[string]string{"error": "template laporan belum memiliki logo"}
+		helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+		return
+	}
+
+	ctx.Data(http.StatusOK, "image/"+strings.ToLower(template.LogoType), template.Logo)
+}
+
+func (service *reportTemplateService) DeleteLogoService(ctx *gin.Context) {
+	template, statusCode, err := service.findTemplate(ctx)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	err = service.repository.UpdateLogoRepository(template.ID, nil, "")
+	if err != nil {
+		response := map[string]string{"error": "gagal menghapus logo"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := map[string]string{"message": fmt.Sprintf("Logo template laporan %s berhasil dihapus", template.Name)}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+func (service *reportTemplateService) findTemplate(ctx *gin.Context) (ReportTemplate, int, error) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return ReportTemplate{}, http.StatusBadRequest, errors.New("ID tidak sesuai")
+	}
+
+	template, err := service.repository.GetReportTemplateByIdRepository(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return template, http.StatusNotFound, fmt.Errorf("Template laporan dengan ID:%d tidak ditemukan", id)
+		}
+		return template, http.StatusInternalServerError, err
+	}
+	return template, http.StatusOK, nil
+}
+
+func saveError(err error) (int, string) {
+	if strings.Contains(err.Error(), "duplicate key") {
+		return http.StatusBadRequest, "nama template laporan sudah ada"
+	}
+	return http.StatusInternalServerError, "gagal menyimpan data template laporan"
+}
@@ -0,0 +1,61 @@
+package sales
+
+import (
+	"backend-profitrack/modules/product"
+	"time"
+)
+
+// Sale adalah satu transaksi penjualan, UnitPrice adalah harga jual saat transaksi terjadi
+type Sale struct {
+	ID        int             `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	ProductID int             `gorm:"integer;not null;index" json:"product_id"`
+	Quantity  int             `gorm:"integer;not null" json:"quantity"`
+	UnitPrice int             `gorm:"integer;not null" json:"unit_price"`
+	SoldAt    time.Time       `gorm:"not null;index" json:"sold_at"`
+	UserID    int             `gorm:"integer" json:"user_id"`
+	Product   product.Product `gorm:"foreignkey:ProductID" json:"-"`
+	CreatedAt time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// RequestSale: unit_price kosong memakai harga jual produk, sold_at (YYYY-MM-DD) kosong memakai hari ini
+type RequestSale struct {
+	ProductID int    `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int    `json:"unit_price"`
+	SoldAt    string `json:"sold_at"`
+}
+
+type ResponseSale struct {
+	ID          int       `json:"id"`
+	ProductID   int       `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Quantity    int       `json:"quantity"`
+	UnitPrice   int       `json:"unit_price"`
+	Total       int       `json:"total"`
+	SoldAt      time.Time `json:"sold_at"`
+}
+
+// Summary adalah total penjualan sebuah produk dalam satu periode setelah dikurangi retur
+type Summary struct {
+	ProductID    int `json:"product_id"`
+	Quantity     int `json:"quantity"`
+	Revenue      int `json:"revenue"`
+	Transactions int `json:"transactions"`
+}
+
+type ResponseSummary struct {
+	ProductID    int    `json:"product_id"`
+	ProductName  string `json:"product_name"`
+	Quantity     int    `json:"quantity"`
+	Revenue      int    `json:"revenue"`
+	Transactions int    `json:"transactions"`
+}
+
+type ResponseSalesSummary struct {
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Quantity int               `json:"quantity"`
+	Revenue  int               `json:"revenue"`
+	Products []ResponseSummary `json:"products"`
+}
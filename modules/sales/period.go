@@ -0,0 +1,69 @@
+package sales
+
+import (
+	"backend-profitrack/modules/product"
+	"errors"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Period adalah rentang tanggal penjualan, From inklusif dan To eksklusif (hari setelah tanggal akhir)
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParsePeriod membaca tanggal awal dan akhir (YYYY-MM-DD, inklusif), ok bernilai false jika keduanya kosong
+func ParsePeriod(from string, to string) (period Period, ok bool, err error) {
+	if from == "" && to == "" {
+		return period, false, nil
+	}
+
+	if from == "" || to == "" {
+		return period, false, errors.New("from dan to harus diisi bersamaan")
+	}
+
+	period.From, err = time.ParseInLocation(dateLayout, from, time.Local)
+	if err != nil {
+		return period, false, errors.New("format from harus YYYY-MM-DD")
+	}
+
+	end, err := time.ParseInLocation(dateLayout, to, time.Local)
+	if err != nil {
+		return period, false, errors.New("format to harus YYYY-MM-DD")
+	}
+
+	if end.Before(period.From) {
+		return period, false, errors.New("to tidak boleh sebelum from")
+	}
+
+	period.To = end.AddDate(0, 0, 1)
+	return period, true, nil
+}
+
+func (p Period) FromDate() string {
+	return p.From.Format(dateLayout)
+}
+
+func (p Period) ToDate() string {
+	return p.To.AddDate(0, 0, -1).Format(dateLayout)
+}
+
+// PeriodFigures mengganti jumlah terjual dan pendapatan setiap produk dengan total penjualan pada periode,
+// produk tanpa penjualan pada periode tersebut bernilai 0
+func PeriodFigures(products []product.Product, summaries []Summary) map[int]product.Figures {
+	summaryMap := make(map[int]Summary, len(summaries))
+	for _, summary := range summaries {
+		summaryMap[summary.ProductID] = summary
+	}
+
+	figures := make(map[int]product.Figures, len(products))
+	for _, getProduct := range products {
+		productFigures := getProduct.Figures()
+		productFigures.Sold = float64(summaryMap[getProduct.ID].Quantity)
+		productFigures.Revenue = float64(summaryMap[getProduct.ID].Revenue)
+		figures[getProduct.ID] = productFigures
+	}
+	return figures
+}
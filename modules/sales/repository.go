@@ -0,0 +1,136 @@
+package sales
+
+import (
+	"backend-profitrack/modules/inventory"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type Repository interface {
+	GetAllSaleRepository(productID int, period *Period) (result []Sale, err error)
+	GetSaleByIdRepository(saleID int) (result Sale, err error)
+	CreateSaleRepository(sale *Sale) (err error)
+	DeleteSaleRepository(sale *Sale, userID int) (err error)
+	SummarizeSalesRepository(period Period) (result []Summary, err error)
+}
+
+type salesRepository struct {
+	DB *gorm.DB
+}
+
+func NewSalesRepository(db *gorm.DB) Repository {
+	return &salesRepository{
+		DB: db,
+	}
+}
+
+// GetAllSaleRepository mengambil penjualan terbaru lebih dulu, productID 0 dan period nil berarti tanpa filter
+func (r *salesRepository) GetAllSaleRepository(productID int, period *Period) (result []Sale, err error) {
+	query := r.DB.Preload("Product").Order("sold_at DESC, id DESC")
+	if productID != 0 {
+		query = query.Where("product_id = ?", productID)
+	}
+	if period != nil {
+		query = query.Where("sold_at >= ? AND sold_at < ?", period.From, period.To)
+	}
+
+	err = query.Find(&result).Error
+	return result, err
+}
+
+func (r *salesRepository) GetSaleByIdRepository(saleID int) (result Sale, err error) {
+	err = r.DB.First(&result, saleID).Error
+	return result, err
+}
+
+// CreateSaleRepository mencatat penjualan, stok keluar dan menambah jumlah terjual produk dalam satu transaksi,
+// mengembalikan inventory.ErrInsufficientStock jika stok produk tidak mencukupi
+func (r *salesRepository) CreateSaleRepository(sale *Sale) (err error) {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err = tx.Create(sale).Error; err != nil {
+			return err
+		}
+
+		err = inventory.ApplyMovement(tx, &inventory.StockMovement{
+			ProductID: sale.ProductID,
+			Type:      inventory.TypeOut,
+			Quantity:  sale.Quantity,
+			Reason:    "penjualan",
+			SaleID:    &sale.ID,
+			UserID:    sale.UserID,
+			MovedAt:   sale.SoldAt,
+			CreatedAt: sale.CreatedAt,
+			UpdatedAt: sale.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Table("products").Where("id = ?", sale.ProductID).
+			Update("sold", gorm.Expr("sold + ?", sale.Quantity)).Error
+	})
+}
+
+// DeleteSaleRepository mengunci lalu menghapus penjualan dalam satu transaksi, stok keluar yang belum diretur dikembalikan
+// dengan pergerakan penyesuaian (jurnal stok tidak dihapus) dan jumlah terjual produk dikurangi sebanyak itu
+func (r *salesRepository) DeleteSaleRepository(sale *Sale, userID int) (err error) {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		// baris penjualan dikunci seperti pada inventory.ApplyReturn agar retur tidak tercatat di antara
+		// penjumlahan pergerakan dan pergerakan penyeimbang
+		if err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&Sale{}, sale.ID).Error; err != nil {
+			return err
+		}
+
+		change, err := inventory.SaleStockChange(tx, sale.ID)
+		if err != nil {
+			return err
+		}
+
+		restored := -change
+		if restored != 0 {
+			now := time.Now()
+			err = inventory.ApplyMovement(tx, &inventory.StockMovement{
+				ProductID: sale.ProductID,
+				Type:      inventory.TypeAdjustment,
+				Quantity:  restored,
+				Reason:    fmt.Sprintf("pembatalan penjualan ID:%d", sale.ID),
+				SaleID:    &sale.ID,
+				UserID:    userID,
+				MovedAt:   sale.SoldAt,
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err = tx.Delete(sale).Error; err != nil {
+			return err
+		}
+
+		return tx.Table("products").Where("id = ?", sale.ProductID).
+			Update("sold", gorm.Expr("GREATEST(sold - ?, 0)", restored)).Error
+	})
+}
+
+// SummarizeSalesRepository menjumlahkan penjualan setiap produk pada periode, jumlah yang diretur dikurangi
+// dari penjualan asalnya sehingga mengurangi quantity dan revenue
+func (r *salesRepository) SummarizeSalesRepository(period Period) (result []Summary, err error) {
+	returns := r.DB.Model(&inventory.StockMovement{}).
+		Select("sale_id, SUM(quantity) AS quantity").
+		Where("type = ?", inventory.TypeReturn).
+		Group("sale_id")
+
+	err = r.DB.Model(&Sale{}).
+		Select("sales.product_id, SUM(sales.quantity - COALESCE(returns.quantity, 0)) AS quantity, "+
+			"SUM((sales.quantity - COALESCE(returns.quantity, 0))::bigint * sales.unit_price) AS revenue, COUNT(*) AS transactions").
+		Joins("LEFT JOIN (?) AS returns ON returns.sale_id = sales.id", returns).
+		Where("sales.sold_at >= ? AND sales.sold_at < ?", period.From, period.To).
+		Group("sales.product_id").
+		Order("sales.product_id ASC").
+		Scan(&result).Error
+	return result, err
+}
@@ -0,0 +1,22 @@
+package sales
+
+import (
+	"backend-profitrack/middleware"
+	"backend-profitrack/modules/product"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	repo := NewSalesRepository(db)
+	productRepo := product.NewProductRepository(db)
+	service := NewSalesService(repo, productRepo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.GET("/sales", service.GetAllSaleService)
+	api.GET("/sales/summary", service.GetSalesSummaryService)
+	api.POST("/sales", service.CreateSaleService)
+	api.DELETE("/sales/:id", service.DeleteSaleService)
+}
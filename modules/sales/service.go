@@ -0,0 +1,249 @@
+package sales
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/inventory"
+	"backend-profitrack/modules/product"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Service interface {
+	GetAllSaleService(ctx *gin.Context)
+	CreateSaleService(ctx *gin.Context)
+	DeleteSaleService(ctx *gin.Context)
+	GetSalesSummaryService(ctx *gin.Context)
+}
+
+type salesService struct {
+	repository        Repository
+	productRepository product.Repository
+}
+
+func NewSalesService(repo Repository, productRepo product.Repository) Service {
+	return &salesService{
+		repository:        repo,
+		productRepository: productRepo,
+	}
+}
+
+// GetAllSaleService menampilkan penjualan, query opsional: product_id, from dan to (YYYY-MM-DD)
+func (service *salesService) GetAllSaleService(ctx *gin.Context) {
+	productID := 0
+	if query := ctx.Query("product_id"); query != "" {
+		var err error
+		productID, err = strconv.Atoi(query)
+		if err != nil {
+			response := map[string]string{"error": "produk ID tidak sesuai"}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	period, withPeriod, err := ParsePeriod(ctx.Query("from"), ctx.Query("to"))
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	var periodFilter *Period
+	if withPeriod {
+		periodFilter = &period
+	}
+
+	sales, err := service.repository.GetAllSaleRepository(productID, periodFilter)
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data penjualan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	var result []ResponseSale
+	for _, sale := range sales {
+		result = append(result, ResponseSale{
+			ID:          sale.ID,
+			ProductID:   sale.ProductID,
+			ProductName: sale.Product.Name,
+			Quantity:    sale.Quantity,
+			UnitPrice:   sale.UnitPrice,
+			Total:       sale.Quantity * sale.UnitPrice,
+			SoldAt:      sale.SoldAt,
+		})
+	}
+
+	if len(result) == 0 {
+		response := map[string]string{"message": "data penjualan masih kosong"}
+		helpers.ResponseJSON(ctx, http.StatusOK, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
+
+func (service *salesService) CreateSaleService(ctx *gin.Context) {
+	var request RequestSale
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if request.Quantity <= 0 || request.UnitPrice < 0 {
+		response := map[string]string{"error": "quantity harus lebih dari 0 dan unit_price tidak boleh negatif"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	getProduct, err := service.productRepository.GetProductByIdRepository(request.ProductID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Produk dengan ID:%d tidak ditemukan", request.ProductID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	soldAt := time.Now()
+	if request.SoldAt != "" {
+		soldAt, err = time.ParseInLocation(dateLayout, request.SoldAt, time.Local)
+		if err != nil {
+			response := map[string]string{"error": "format sold_at harus YYYY-MM-DD"}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	unitPrice := request.UnitPrice
+	if unitPrice == 0 {
+		unitPrice = getProduct.PriceSale
+	}
+
+	newSale := Sale{
+		ProductID: getProduct.ID,
+		Quantity:  request.Quantity,
+		UnitPrice: unitPrice,
+		SoldAt:    soldAt,
+		UserID:    ctx.GetInt("user_id"),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err = service.repository.CreateSaleRepository(&newSale)
+	if err != nil {
+		if errors.Is(err, inventory.ErrInsufficientStock) {
+			response := map[string]string{"error": fmt.Sprintf("stok produk %s tidak mencukupi, sisa stok %d", getProduct.Name, getProduct.Stock)}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+		response := map[string]string{"error": "gagal menyimpan data penjualan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusCreated, ResponseSale{
+		ID:          newSale.ID,
+		ProductID:   newSale.ProductID,
+		ProductName: getProduct.Name,
+		Quantity:    newSale.Quantity,
+		UnitPrice:   newSale.UnitPrice,
+		Total:       newSale.Quantity * newSale.UnitPrice,
+		SoldAt:      newSale.SoldAt,
+	})
+}
+
+func (service *salesService) DeleteSaleService(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	sale, err := service.repository.GetSaleByIdRepository(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Penjualan dengan ID:%d tidak ditemukan", id)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	err = service.repository.DeleteSaleRepository(&sale, ctx.GetInt("user_id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// penjualan sudah dihapus oleh permintaan lain
+		response := map[string]string{"error": fmt.Sprintf("Penjualan dengan ID:%d tidak ditemukan", id)}
+		helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+		return
+	}
+	if err != nil {
+		response := map[string]string{"error": "gagal menghapus data penjualan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := map[string]string{"message": fmt.Sprintf("Penjualan dengan ID:%d berhasil dihapus", id)}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+// GetSalesSummaryService menghitung jumlah terjual dan pendapatan setiap produk pada periode from-to
+func (service *salesService) GetSalesSummaryService(ctx *gin.Context) {
+	period, withPeriod, err := ParsePeriod(ctx.Query("from"), ctx.Query("to"))
+	if err == nil && !withPeriod {
+		err = errors.New("from dan to harus diisi")
+	}
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	summaries, err := service.repository.SummarizeSalesRepository(period)
+	if err != nil {
+		response := map[string]string{"error": "gagal menghitung ringkasan penjualan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	products, err := service.productRepository.GetAllProductRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data produk"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	productNames := make(map[int]string, len(products))
+	for _, getProduct := range products {
+		productNames[getProduct.ID] = getProduct.Name
+	}
+
+	result := ResponseSalesSummary{
+		From:     period.FromDate(),
+		To:       period.ToDate(),
+		Products: []ResponseSummary{},
+	}
+	for _, summary := range summaries {
+		result.Quantity += summary.Quantity
+		result.Revenue += summary.Revenue
+		result.Products = append(result.Products, ResponseSummary{
+			ProductID:    summary.ProductID,
+			ProductName:  productNames[summary.ProductID],
+			Quantity:     summary.Quantity,
+			Revenue:      summary.Revenue,
+			Transactions: summary.Transactions,
+		})
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
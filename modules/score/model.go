@@ -1,22 +1,69 @@
 package score
 
 import (
+	"backend-profitrack/modules/calculation_run"
 	"backend-profitrack/modules/criteria"
 	"backend-profitrack/modules/method"
 	"backend-profitrack/modules/product"
+	"backend-profitrack/modules/ranking"
+	"fmt"
 	"time"
 )
 
 type Score struct {
-	ID         int               `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
-	ProductID  int               `gorm:"integer;not null" json:"product_id"`
-	CriteriaID int               `gorm:"integer;not null" json:"criteria_id"`
-	MethodID   int               `gorm:"integer;not null" json:"method_id"`
-	ScoreOne   float64           `gorm:"double" json:"score_one"`
-	ScoreTwo   float64           `gorm:"double" json:"score_two"`
-	Product    product.Product   `gorm:"foreignkey:ProductID" json:"-"`
-	Criteria   criteria.Criteria `gorm:"foreignkey:CriteriaID" json:"-"`
-	Method     method.Method     `gorm:"foreignkey:MethodID" json:"-"`
-	CreatedAt  time.Time         `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt  time.Time         `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID               int               `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	ProductID        int               `gorm:"integer;not null" json:"product_id"`
+	CriteriaID       int               `gorm:"integer;not null" json:"criteria_id"`
+	MethodID         int               `gorm:"integer;not null" json:"method_id"`
+	CalculationRunID int               `gorm:"integer;index" json:"calculation_run_id"`
+	ScoreOne         float64           `gorm:"double" json:"score_one"`
+	ScoreTwo         float64           `gorm:"double" json:"score_two"`
+	Product          product.Product   `gorm:"foreignkey:ProductID" json:"-"`
+	Criteria         criteria.Criteria `gorm:"foreignkey:CriteriaID" json:"-"`
+	Method           method.Method     `gorm:"foreignkey:MethodID" json:"-"`
+	CreatedAt        time.Time         `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time         `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// RequestConsensus berisi metode yang skor akhirnya digabungkan oleh metode konsensus
+type RequestConsensus struct {
+	SourceMethodIDs []int `json:"source_method_ids"`
+}
+
+// Progress adalah posisi perhitungan: persentase keseluruhan (0-100), tahap yang sedang berjalan
+// dan jumlah baris yang sudah ditulis pada tahap penyimpanan (0 pada tahap lain)
+type Progress struct {
+	Percent float64 `json:"percent"`
+	Stage   string  `json:"stage"`
+	Done    int     `json:"done"`
+	Total   int     `json:"total"`
+}
+
+type ProgressFunc func(progress Progress)
+
+// Calculation adalah hasil RunCalculation beserta data yang dipakai untuk trace
+type Calculation struct {
+	Method    method.Method
+	Algorithm ranking.Algorithm
+	Matrix    ranking.Matrix
+	Result    ranking.Result
+	Run       *calculation_run.CalculationRun
+}
+
+// CalculationError menyimpan status HTTP dan tahap perhitungan yang gagal
+type CalculationError struct {
+	StatusCode int
+	Process    string
+	Err        error
+}
+
+func (e *CalculationError) Error() string {
+	if e.Process != "" {
+		return fmt.Sprintf("%s: %v", e.Process, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *CalculationError) Unwrap() error {
+	return e.Err
 }
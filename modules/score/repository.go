@@ -1,22 +1,50 @@
 package score
 
 import (
+	"backend-profitrack/modules/calculation_run"
 	"backend-profitrack/modules/final_score"
 	"backend-profitrack/modules/report"
+	"context"
+	"errors"
 	"gorm.io/gorm"
+	"time"
 )
 
 type Repository interface {
 	GetAllScoreByMethodIDRepository(methodID int) (result []Score, err error)
-	GetScoreByProductAndCriteriaAndMethodRepository(productID int, criteriaID int, methodID int) (result Score, err error)
-	CreateScoreRepository(score *Score) (err error)
-	CreateFinalScoreByMethodIDRepository(methodID int, finalScore *final_score.FinalScore) (err error)
-	CreateReportFinalScoreByMethodIDRepository(report *report.Report) (err error)
-	DeleteAllScoresByMethodIDRepository(methodID int) (err error)
-	CreateReportDetailRepository(reportDetail *report.ReportDetail) (err error)
-	UpdateScoreByMethodIDRepository(methodID int, score *Score) (err error)
-	UpdateFinalScoreByMethodIDRepository(methodID int, finalScore *final_score.FinalScore) (err error)
-	DeleteFinalScoreByMethodIDRepository(methodID int) (err error)
+	CreateReportByMethodIDRepository(methodID int, newReport *report.Report, details []report.ReportDetail) (err error)
+	GetCalculationRunByIdRepository(runID int) (result calculation_run.CalculationRun, err error)
+	GetLatestCalculationRunByMethodIDRepository(methodID int) (result calculation_run.CalculationRun, err error)
+	GetFinalScoresByRunIdRepository(runID int) (result []final_score.FinalScore, err error)
+	GetReportDetailsByRunIdRepository(runID int) (result []report.ReportDetail, err error)
+	ReplaceScoresByMethodIDRepository(ctx context.Context, run *calculation_run.CalculationRun, scores []Score, finalScores []final_score.FinalScore, progress func(written int, total int)) (err error)
+}
+
+// batchSize adalah jumlah baris per INSERT saat menyimpan hasil perhitungan
+const batchSize = 500
+
+// methodLockSpace adalah kunci pertama pg_advisory_xact_lock(key1, key2) untuk perhitungan metode,
+// kunci kedua adalah ID metode
+const methodLockSpace = 1
+
+// ErrMethodLocked dikembalikan jika hasil metode sedang ditulis oleh transaksi lain, termasuk dari instance server lain
+var ErrMethodLocked = errors.New("hasil perhitungan metode sedang ditulis oleh proses lain")
+
+// lockMethod mengambil advisory lock metode sampai transaksi selesai, wait=false langsung mengembalikan
+// ErrMethodLocked jika lock sedang dipegang transaksi lain
+func lockMethod(tx *gorm.DB, methodID int, wait bool) error {
+	if wait {
+		return tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", methodLockSpace, methodID).Error
+	}
+
+	var locked bool
+	if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?, ?)", methodLockSpace, methodID).Scan(&locked).Error; err != nil {
+		return err
+	}
+	if !locked {
+		return ErrMethodLocked
+	}
+	return nil
 }
 
 type scoreRepository struct {
@@ -36,47 +64,125 @@ func (r *scoreRepository) GetAllScoreByMethodIDRepository(methodID int) (result
 	return result, err
 }
 
-func (r *scoreRepository) GetScoreByProductAndCriteriaAndMethodRepository(productID int, criteriaID int, methodID int) (result Score, err error) {
-	err = r.DB.Where("product_id = ? AND criteria_id = ? AND method_id = ?", productID, criteriaID, methodID).Find(&result).Error
-	return result, err
-}
+// CreateReportByMethodIDRepository menyimpan laporan beserta detailnya lalu menghapus nilai dan nilai akhir
+// metode tersebut dalam satu transaksi, sehingga laporan tidak pernah tersimpan setengah. Laporan menunggu
+// perhitungan metode yang sedang menulis hasilnya selesai
+func (r *scoreRepository) CreateReportByMethodIDRepository(methodID int, newReport *report.Report, details []report.ReportDetail) (err error) {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err = lockMethod(tx, methodID, true); err != nil {
+			return err
+		}
 
-func (r *scoreRepository) CreateScoreRepository(score *Score) (err error) {
-	err = r.DB.Create(score).Error
-	return err
-}
+		if err = tx.Create(newReport).Error; err != nil {
+			return err
+		}
 
-func (r *scoreRepository) CreateFinalScoreByMethodIDRepository(methodID int, finalScore *final_score.FinalScore) (err error) {
-	err = r.DB.Where("method_id = ?", methodID).Create(finalScore).Error
-	return err
-}
+		for i := range details {
+			details[i].ReportID = newReport.ID
+		}
+
+		if len(details) > 0 {
+			if err = tx.CreateInBatches(&details, batchSize).Error; err != nil {
+				return err
+			}
+		}
 
-func (r *scoreRepository) UpdateFinalScoreByMethodIDRepository(methodID int, finalScore *final_score.FinalScore) (err error) {
-	err = r.DB.Where("method_id = ?", methodID).Save(finalScore).Error
-	return err
+		if err = tx.Where("method_id = ?", methodID).Delete(&Score{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("method_id = ?", methodID).Delete(&final_score.FinalScore{}).Error
+	})
 }
 
-func (r *scoreRepository) UpdateScoreByMethodIDRepository(methodID int, score *Score) (err error) {
-	err = r.DB.Where("method_id = ?", methodID).Save(score).Error
-	return err
+// ReplaceScoresByMethodIDRepository mencatat riwayat perhitungan lalu mengganti seluruh nilai dan nilai akhir
+// metode tersebut dalam satu transaksi, progress (boleh nil) dipanggil setiap satu batch selesai ditulis
+// dan pembatalan ctx me-rollback transaksi. Metode yang sedang ditulis transaksi lain mengembalikan ErrMethodLocked
+func (r *scoreRepository) ReplaceScoresByMethodIDRepository(ctx context.Context, run *calculation_run.CalculationRun, scores []Score, finalScores []final_score.FinalScore, progress func(written int, total int)) (err error) {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err = lockMethod(tx, run.MethodID, false); err != nil {
+			return err
+		}
+
+		if err = tx.Create(run).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Where("method_id = ?", run.MethodID).Delete(&Score{}).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Where("method_id = ?", run.MethodID).Delete(&final_score.FinalScore{}).Error; err != nil {
+			return err
+		}
+
+		for i := range scores {
+			scores[i].CalculationRunID = run.ID
+		}
+		for i := range finalScores {
+			finalScores[i].CalculationRunID = run.ID
+		}
+
+		total := len(scores) + len(finalScores)
+		written := 0
+		for start := 0; start < len(scores); start += batchSize {
+			end := min(start+batchSize, len(scores))
+			if err = tx.Create(scores[start:end]).Error; err != nil {
+				return err
+			}
+
+			written += end - start
+			if progress != nil {
+				progress(written, total)
+			}
+		}
+
+		for start := 0; start < len(finalScores); start += batchSize {
+			end := min(start+batchSize, len(finalScores))
+			if err = tx.Create(finalScores[start:end]).Error; err != nil {
+				return err
+			}
+
+			written += end - start
+			if progress != nil {
+				progress(written, total)
+			}
+		}
+
+		// durasi dihitung sampai seluruh data selesai ditulis
+		run.DurationMs = time.Since(run.CreatedAt).Milliseconds()
+		return tx.Model(run).Update("duration_ms", run.DurationMs).Error
+	})
 }
 
-func (r *scoreRepository) DeleteAllScoresByMethodIDRepository(methodID int) (err error) {
-	err = r.DB.Where("method_id = ?", methodID).Delete(&Score{}).Error
-	return err
+func (r *scoreRepository) GetCalculationRunByIdRepository(runID int) (result calculation_run.CalculationRun, err error) {
+	err = r.DB.First(&result, runID).Error
+	return result, err
 }
 
-func (r *scoreRepository) CreateReportFinalScoreByMethodIDRepository(report *report.Report) (err error) {
-	err = r.DB.Create(report).Error
-	return err
+// GetLatestCalculationRunByMethodIDRepository tidak memuat nilai agregasi yang berukuran sebanding jumlah produk
+func (r *scoreRepository) GetLatestCalculationRunByMethodIDRepository(methodID int) (result calculation_run.CalculationRun, err error) {
+	err = r.DB.Omit("aggregation").Where("method_id = ?", methodID).Order("id DESC").First(&result).Error
+	return result, err
 }
 
-func (r *scoreRepository) CreateReportDetailRepository(reportDetail *report.ReportDetail) (err error) {
-	err = r.DB.Create(reportDetail).Error
-	return err
+func (r *scoreRepository) GetFinalScoresByRunIdRepository(runID int) (result []final_score.FinalScore, err error) {
+	err = r.DB.Where("calculation_run_id = ?", runID).Find(&result).Error
+	return result, err
 }
 
-func (r *scoreRepository) DeleteFinalScoreByMethodIDRepository(methodID int) (err error) {
-	err = r.DB.Where("method_id = ?", methodID).Delete(&final_score.FinalScore{}).Error
-	return err
+// GetReportDetailsByRunIdRepository mengambil detail laporan terbaru dari sebuah riwayat perhitungan, dipakai
+// setelah nilai akhir metode dihapus karena sudah dijadikan laporan
+func (r *scoreRepository) GetReportDetailsByRunIdRepository(runID int) (result []report.ReportDetail, err error) {
+	var getReport report.Report
+	err = r.DB.Where("calculation_run_id = ?", runID).Order("id DESC").First(&getReport).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.DB.Where("report_id = ?", getReport.ID).Find(&result).Error
+	return result, err
 }
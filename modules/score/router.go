@@ -6,6 +6,7 @@ import (
 	"backend-profitrack/modules/criteria_score"
 	"backend-profitrack/modules/final_score"
 	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
 	"backend-profitrack/modules/product"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -18,18 +19,25 @@ func Initiator(router *gin.Engine, db *gorm.DB) {
 	methodRepo := method.NewMethodRepository(db)
 	criteriaScoreRepo := criteria_score.NewCriteriaScoreRepository(db)
 	finalScoreRepo := final_score.NewFinalScoreRepository(db)
-	service := NewScoreService(repo, productRepo, criteriaRepo, methodRepo, criteriaScoreRepo, finalScoreRepo)
+	periodRepo := period.NewPeriodRepository(db)
+	service := NewScoreService(repo, productRepo, criteriaRepo, methodRepo, criteriaScoreRepo, finalScoreRepo, periodRepo)
 
 	api := router.Group("/api")
 	api.Use(middleware.LoggingMiddleware())
 	api.Use(middleware.JWTMiddleware())
 	api.GET("/scores/:methodID", service.GetAllScoreByMethodIDService)
 
-	//SMART
-	api.POST("/scores/:methodID/SMART", service.CalculateSMARTService)
+	// Perhitungan berdasarkan algoritma yang terhubung dengan metode
+	api.POST("/scores/:methodID/calculate", service.CalculateService)
+	api.POST("/scores/:methodID/calculate/stream", service.CalculateStreamService)
 
-	//MOORA
-	api.POST("/scores/:methodID/MOORA", service.CalculateMOORAService)
+	// Penggabungan skor akhir beberapa metode oleh metode konsensus
+	api.POST("/scores/:methodID/consensus", service.CalculateConsensusService)
+
+	// Rute lama tetap dipertahankan, algoritma pada rute harus sama dengan algoritma metode
+	api.POST("/scores/:methodID/SMART", service.CalculateAlgorithmService("SMART"))
+	api.POST("/scores/:methodID/MOORA", service.CalculateAlgorithmService("MOORA"))
+	api.POST("/scores/:methodID/TOPSIS", service.CalculateAlgorithmService("TOPSIS"))
 
 	// Create Final Scores and Report
 	api.POST("/final_scores/:methodID", service.CreateReportByMethodIDService)
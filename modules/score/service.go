@@ -2,28 +2,43 @@ package score
 
 import (
 	"backend-profitrack/helpers"
+	"backend-profitrack/modules/calculation_run"
 	"backend-profitrack/modules/criteria"
 	"backend-profitrack/modules/criteria_score"
 	"backend-profitrack/modules/final_score"
 	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
 	"backend-profitrack/modules/product"
+	"backend-profitrack/modules/ranking"
 	"backend-profitrack/modules/report"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
-	"math"
+	"gorm.io/gorm"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 type Service interface {
 	GetAllScoreByMethodIDService(ctx *gin.Context)
-	CalculateSMARTService(ctx *gin.Context)
-	CalculateMOORAService(ctx *gin.Context)
+	CalculateService(ctx *gin.Context)
+	CalculateAlgorithmService(algorithm string) gin.HandlerFunc
+	CalculateStreamService(ctx *gin.Context)
+	RunCalculation(ctx context.Context, methodID int, periodID int, userID int, progress ProgressFunc) (Calculation, error)
+	CalculateConsensusService(ctx *gin.Context)
 	CreateReportByMethodIDService(ctx *gin.Context)
 }
 
+// running menandai metode yang sedang dihitung agar tidak berjalan bersamaan pada proses ini, dipakai bersama
+// oleh endpoint perhitungan dan job di background. Antar instance server, penulisan hasil dijaga advisory lock
+// (lockMethod) dan perhitungan yang kalah mendapat status 409
+var running sync.Map
+
 type scoreService struct {
 	repository              Repository
 	productRepository       product.Repository
@@ -31,9 +46,10 @@ type scoreService struct {
 	methodRepository        method.Repository
 	criteriaScoreRepository criteria_score.Repository
 	finalScoreRepository    final_score.Repository
+	periodRepository        period.Repository
 }
 
-func NewScoreService(repo Repository, productRepo product.Repository, criteriaRepo criteria.Repository, methodRepo method.Repository, criteriaScoreRepo criteria_score.Repository, finalScoreRepo final_score.Repository) Service {
+func NewScoreService(repo Repository, productRepo product.Repository, criteriaRepo criteria.Repository, methodRepo method.Repository, criteriaScoreRepo criteria_score.Repository, finalScoreRepo final_score.Repository, periodRepo period.Repository) Service {
 	return &scoreService{
 		repository:              repo,
 		productRepository:       productRepo,
@@ -41,6 +57,7 @@ func NewScoreService(repo Repository, productRepo product.Repository, criteriaRe
 		methodRepository:        methodRepo,
 		criteriaScoreRepository: criteriaScoreRepo,
 		finalScoreRepository:    finalScoreRepo,
+		periodRepository:        periodRepo,
 	}
 }
 
@@ -62,14 +79,15 @@ func (service *scoreService) GetAllScoreByMethodIDService(ctx *gin.Context) {
 	var result []Score
 	for _, score := range scores {
 		result = append(result, Score{
-			ID:         score.ID,
-			ProductID:  score.ProductID,
-			CriteriaID: score.CriteriaID,
-			ScoreOne:   score.ScoreOne,
-			ScoreTwo:   score.ScoreTwo,
-			MethodID:   score.MethodID,
-			CreatedAt:  score.CreatedAt,
-			UpdatedAt:  score.UpdatedAt,
+			ID:               score.ID,
+			ProductID:        score.ProductID,
+			CriteriaID:       score.CriteriaID,
+			ScoreOne:         score.ScoreOne,
+			ScoreTwo:         score.ScoreTwo,
+			MethodID:         score.MethodID,
+			CalculationRunID: score.CalculationRunID,
+			CreatedAt:        score.CreatedAt,
+			UpdatedAt:        score.UpdatedAt,
 		})
 	}
 
@@ -82,7 +100,40 @@ func (service *scoreService) GetAllScoreByMethodIDService(ctx *gin.Context) {
 	}
 }
 
-func (service *scoreService) CalculateSMARTService(ctx *gin.Context) {
+// CalculateAlgorithmService melayani rute lama /scores/:methodID/<algoritma>, perhitungan ditolak jika
+// algoritma pada rute berbeda dengan algoritma metode agar rute tidak menjalankan algoritma lain
+func (service *scoreService) CalculateAlgorithmService(algorithm string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		methodID, err := strconv.Atoi(ctx.Param("methodID"))
+		if err != nil {
+			response := map[string]string{"error": "ID tidak sesuai"}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+
+		getMethod, err := service.methodRepository.GetMethodByIdRepository(methodID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				response := map[string]string{"error": fmt.Sprintf("Metode dengan ID:%d tidak ditemukan", methodID)}
+				helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+				return
+			}
+			response := map[string]string{"error": err.Error()}
+			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+			return
+		}
+
+		if !strings.EqualFold(getMethod.Algorithm, algorithm) {
+			response := map[string]string{"error": fmt.Sprintf("metode %s memakai algoritma %s, bukan %s, gunakan /scores/%d/calculate", getMethod.Name, getMethod.Algorithm, algorithm, methodID)}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+
+		service.CalculateService(ctx)
+	}
+}
+
+func (service *scoreService) CalculateService(ctx *gin.Context) {
 	methodID, err := strconv.Atoi(ctx.Param("methodID"))
 	if err != nil {
 		response := map[string]string{"error": "ID tidak sesuai"}
@@ -90,38 +141,44 @@ func (service *scoreService) CalculateSMARTService(ctx *gin.Context) {
 		return
 	}
 
-	startTime := time.Now()
-
-	// perhitungan utility score
-	err = service.utilityScoreSMART(methodID)
+	// period_id memakai nilai kriteria periode tersebut, tanpa period_id memakai data produk saat ini
+	getPeriod, statusCode, err := period.Resolve(service.periodRepository, ctx.Query("period_id"))
 	if err != nil {
-		response := map[string]interface{}{
-			"error":   err.Error(),
-			"process": "Perhitungan utility nilai SMART",
-		}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
 		return
 	}
 
-	// perhitungan score times weight
-	err = service.scoreOneTimesWeightByMethodID(methodID)
-	if err != nil {
-		response := map[string]interface{}{
-			"error":   err.Error(),
-			"process": "Perhitungan score one x bobot",
-		}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+	// trace=true menyertakan seluruh tahap perhitungan, format=xlsx mengunduhnya sebagai workbook
+	withTrace := ctx.Query("trace") == "true"
+	format := strings.ToLower(ctx.Query("format"))
+	if format != "" && (format != "xlsx" || !withTrace) {
+		response := map[string]string{"error": "format hanya mendukung xlsx bersama trace=true"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
 		return
 	}
 
-	// perhitungan final score
-	err = service.createFinalScoresSMART(methodID)
+	startTime := time.Now()
+
+	// perhitungan dibatalkan (dan transaksi di-rollback) jika koneksi klien terputus
+	calculation, err := service.RunCalculation(ctx.Request.Context(), methodID, getPeriod.ID, ctx.GetInt("user_id"), nil)
 	if err != nil {
-		response := map[string]interface{}{
-			"error":   err.Error(),
-			"process": "Perhitungan final score SMART",
+		var calculationErr *CalculationError
+		if errors.As(err, &calculationErr) && calculationErr.Process != "" {
+			response := map[string]interface{}{
+				"error":   calculationErr.Err.Error(),
+				"process": calculationErr.Process,
+			}
+			helpers.ResponseJSON(ctx, calculationErr.StatusCode, response)
+			return
 		}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+
+		statusCode = http.StatusInternalServerError
+		if errors.As(err, &calculationErr) {
+			statusCode = calculationErr.StatusCode
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
 		return
 	}
 
@@ -130,14 +187,33 @@ func (service *scoreService) CalculateSMARTService(ctx *gin.Context) {
 	processingTime := endTime.Sub(startTime)
 
 	response := map[string]interface{}{
-		"message":        "Normalisasi nilai utility, utility x bobot, dan perhitungan skor akhir SMART berhasil",
+		"message":        fmt.Sprintf("Normalisasi, pembobotan, dan perhitungan skor akhir %s berhasil", calculation.Method.Name),
 		"processingTime": processingTime.String(),
+		"runID":          calculation.Run.ID,
+		"periodID":       calculation.Run.PeriodID,
 	}
+
+	if withTrace {
+		trace, err := service.calculationTrace(calculation.Algorithm, calculation.Matrix, calculation.Result)
+		if err != nil {
+			response := map[string]string{"error": "gagal mengambil data produk"}
+			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+			return
+		}
+
+		if format == "xlsx" {
+			service.exportTrace(ctx, calculation.Method.Name, trace)
+			return
+		}
+		response["trace"] = trace
+	}
+
 	helpers.ResponseJSON(ctx, http.StatusOK, response)
 }
 
-// CalculateMOORAService
-func (service *scoreService) CalculateMOORAService(ctx *gin.Context) {
+// CalculateStreamService menjalankan perhitungan seperti CalculateService dan mengirim progres sebagai
+// Server-Sent Events: "progress" untuk setiap tahap, lalu "result" atau "error" sebagai event terakhir
+func (service *scoreService) CalculateStreamService(ctx *gin.Context) {
 	methodID, err := strconv.Atoi(ctx.Param("methodID"))
 	if err != nil {
 		response := map[string]string{"error": "ID tidak sesuai"}
@@ -145,54 +221,139 @@ func (service *scoreService) CalculateMOORAService(ctx *gin.Context) {
 		return
 	}
 
-	startTime := time.Now()
-
-	// normalisasi score MOORA
-	err = service.normalizeScoreMOORA(methodID)
+	getPeriod, statusCode, err := period.Resolve(service.periodRepository, ctx.Query("period_id"))
 	if err != nil {
-		response := map[string]interface{}{
-			"error":   err.Error(),
-			"process": "Normalisasi nilai MOORA",
-		}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
 		return
 	}
 
-	// perhitungan score times weight
-	err = service.scoreOneTimesWeightByMethodID(methodID)
-	if err != nil {
-		response := map[string]interface{}{
-			"error":   err.Error(),
-			"process": "Perhitungan score one x bobot",
+	requestCtx := ctx.Request.Context()
+	userID := ctx.GetInt("user_id")
+	events := make(chan helpers.Event, 16)
+	send := helpers.EventSender(requestCtx, events)
+
+	go func() {
+		defer close(events)
+		startTime := time.Now()
+
+		calculation, err := service.RunCalculation(requestCtx, methodID, getPeriod.ID, userID, func(progress Progress) {
+			send("progress", progress)
+		})
+		if err != nil {
+			send("error", map[string]string{"error": err.Error()})
+			return
 		}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
-		return
+
+		send("result", map[string]interface{}{
+			"message":        fmt.Sprintf("Normalisasi, pembobotan, dan perhitungan skor akhir %s berhasil", calculation.Method.Name),
+			"processingTime": time.Since(startTime).String(),
+			"runID":          calculation.Run.ID,
+			"periodID":       calculation.Run.PeriodID,
+		})
+	}()
+
+	helpers.StreamEvents(ctx, events)
+}
+
+// RunCalculation menghitung dan menyimpan skor akhir sebuah metode dari nilai kriteria periode periodID
+// (0 untuk data produk saat ini), dipakai oleh endpoint perhitungan dan oleh job di background.
+// progress (boleh nil) dipanggil setiap tahap dan setiap batch penyimpanan
+func (service *scoreService) RunCalculation(ctx context.Context, methodID int, periodID int, userID int, progress ProgressFunc) (Calculation, error) {
+	var calculation Calculation
+	if progress == nil {
+		progress = func(Progress) {}
 	}
 
-	// Menghitung final score
-	err = service.createFinalScoresMOORA(methodID)
+	getMethod, err := service.methodRepository.GetMethodByIdRepository(methodID)
 	if err != nil {
-		response := map[string]interface{}{
-			"error":   err.Error(),
-			"process": "Perhitungan final score MOORA",
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return calculation, &CalculationError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("Metode dengan ID:%d tidak ditemukan", methodID)}
 		}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
-		return
+		return calculation, &CalculationError{StatusCode: http.StatusInternalServerError, Err: err}
 	}
+	calculation.Method = getMethod
 
-	// Menghitung selisih waktu
-	endTime := time.Now()
-	processingTime := endTime.Sub(startTime)
+	if _, ok := ranking.GetConsensus(getMethod.Algorithm); ok {
+		return calculation, &CalculationError{StatusCode: http.StatusBadRequest, Err: fmt.Errorf("metode %s adalah metode konsensus, gunakan /scores/%d/consensus", getMethod.Name, methodID)}
+	}
 
-	// Kirim response dengan waktu proses
-	response := map[string]interface{}{
-		"message":        "Normalisasi nilai normalisasi, normalisasi x bobot, dan perhitungan skor akhir MOORA berhasil",
-		"processingTime": processingTime.String(),
+	algorithm, ok := ranking.Get(getMethod.Algorithm)
+	if !ok {
+		return calculation, &CalculationError{StatusCode: http.StatusBadRequest, Err: fmt.Errorf("metode %s belum terhubung dengan algoritma perhitungan", getMethod.Name)}
 	}
-	helpers.ResponseJSON(ctx, http.StatusOK, response)
+	calculation.Algorithm = algorithm
+
+	if periodID != 0 {
+		_, err = service.periodRepository.GetPeriodByIdRepository(periodID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return calculation, &CalculationError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("Periode dengan ID:%d tidak ditemukan", periodID)}
+		}
+		if err != nil {
+			return calculation, &CalculationError{StatusCode: http.StatusInternalServerError, Err: err}
+		}
+	}
+
+	if _, busy := running.LoadOrStore(methodID, true); busy {
+		return calculation, &CalculationError{StatusCode: http.StatusConflict, Err: fmt.Errorf("perhitungan metode %s sedang berjalan", getMethod.Name)}
+	}
+	defer running.Delete(methodID)
+
+	startTime := time.Now()
+
+	// menyusun matriks keputusan dari nilai kriteria
+	progress(Progress{Percent: 0, Stage: "Penyusunan matriks keputusan"})
+	criteriaList, matrix, err := service.decisionMatrix(periodID)
+	if errors.Is(err, criteria.ErrWeightSum) {
+		return calculation, &CalculationError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+	if err != nil {
+		return calculation, &CalculationError{StatusCode: http.StatusInternalServerError, Process: "Penyusunan matriks keputusan", Err: err}
+	}
+	calculation.Matrix = matrix
+
+	if err = ctx.Err(); err != nil {
+		return calculation, &CalculationError{StatusCode: http.StatusRequestTimeout, Err: err}
+	}
+
+	// normalisasi -> pembobotan -> agregasi dijalankan sekaligus sehingga tidak memiliki jumlah item
+	progress(Progress{Percent: 10, Stage: fmt.Sprintf("Perhitungan %s", algorithm.Name())})
+	calculation.Result = ranking.Run(algorithm, matrix)
+
+	if err = ctx.Err(); err != nil {
+		return calculation, &CalculationError{StatusCode: http.StatusRequestTimeout, Err: err}
+	}
+
+	calculation.Run = &calculation_run.CalculationRun{
+		MethodID:     methodID,
+		PeriodID:     periodID,
+		Algorithm:    algorithm.Name(),
+		UserID:       userID,
+		Criteria:     criteria.NewSnapshot(criteriaList),
+		ProductCount: len(matrix.ProductIDs),
+		Aggregation:  runAggregation(algorithm, matrix, calculation.Result),
+		CreatedAt:    startTime,
+		UpdatedAt:    startTime,
+	}
+
+	// penyimpanan dihitung sebagai 20% - 100% dari progres
+	err = service.saveResult(ctx, calculation.Run, matrix, calculation.Result, func(written int, total int) {
+		progress(Progress{Percent: 20 + 80*float64(written)/float64(total), Stage: "Penyimpanan hasil perhitungan", Done: written, Total: total})
+	})
+	if errors.Is(err, ErrMethodLocked) {
+		return calculation, &CalculationError{StatusCode: http.StatusConflict, Err: fmt.Errorf("perhitungan metode %s sedang berjalan", getMethod.Name)}
+	}
+	if err != nil {
+		return calculation, &CalculationError{StatusCode: http.StatusInternalServerError, Process: fmt.Sprintf("Penyimpanan hasil perhitungan %s", getMethod.Name), Err: err}
+	}
+
+	progress(Progress{Percent: 100, Stage: "Selesai"})
+	return calculation, nil
 }
 
-func (service *scoreService) CreateReportByMethodIDService(ctx *gin.Context) {
+// CalculateConsensusService menggabungkan skor akhir metode sumber dengan metode konsensus (Borda, Copeland,
+// rata-rata peringkat) dan menyimpannya sebagai skor akhir metode konsensus sehingga dapat dibuat laporannya
+func (service *scoreService) CalculateConsensusService(ctx *gin.Context) {
 	methodID, err := strconv.Atoi(ctx.Param("methodID"))
 	if err != nil {
 		response := map[string]string{"error": "ID tidak sesuai"}
@@ -200,353 +361,382 @@ func (service *scoreService) CreateReportByMethodIDService(ctx *gin.Context) {
 		return
 	}
 
-	// Mendapatkan data final score by method id
-	finalScores, err := service.finalScoreRepository.GetAllFinalScoreByMethodIDRepository(methodID)
+	getMethod, err := service.methodRepository.GetMethodByIdRepository(methodID)
 	if err != nil {
-		response := map[string]string{"error": "ID tidak sesuai"}
-		helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Metode dengan ID:%d tidak ditemukan", methodID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
 		return
 	}
 
-	if len(finalScores) == 0 {
-		response := map[string]string{"error": "data nilai akhir masih kosong"}
-		helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+	consensus, ok := ranking.GetConsensus(getMethod.Algorithm)
+	if !ok {
+		response := map[string]string{"error": fmt.Sprintf("metode %s bukan metode konsensus", getMethod.Name)}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
 		return
 	}
 
-	newReport := report.Report{
-		ReportCode: fmt.Sprintf("LAP-%d-%d", methodID, time.Now().Unix()),
-		MethodID:   methodID,
-		TotalData:  len(finalScores),
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-	}
-
-	err = service.repository.CreateReportFinalScoreByMethodIDRepository(&newReport)
-	if err != nil {
-		response := map[string]string{"error": "gagal membuat laporan utama"}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+	var request RequestConsensus
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
 		return
 	}
 
-	for _, score := range finalScores {
-		reportDetail := report.ReportDetail{
-			MethodID:   methodID,
-			ProductID:  score.ProductID,
-			ReportID:   newReport.ID,
-			FinalScore: score.FinalScore,
-			CreatedAt:  time.Now(),
-			UpdatedAt:  time.Now(),
-		}
-
-		err = service.repository.CreateReportDetailRepository(&reportDetail)
-		if err != nil {
-			response := map[string]string{"error": "gagal memasukkan nilai ke dalam detail laporan"}
-			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+	sourceIDs := make(map[int]bool)
+	for _, sourceID := range request.SourceMethodIDs {
+		if sourceID == methodID || sourceIDs[sourceID] {
+			response := map[string]string{"error": "source_method_ids tidak boleh berisi ID yang sama atau metode konsensus itu sendiri"}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
 			return
 		}
+		sourceIDs[sourceID] = true
 	}
 
-	err = service.repository.DeleteAllScoresByMethodIDRepository(methodID)
-	if err != nil {
-		response := map[string]string{"error": "gagal menghapus semua data nilai"}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+	if len(request.SourceMethodIDs) < 2 {
+		response := map[string]string{"error": "source_method_ids minimal berisi 2 metode"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
 		return
 	}
 
-	err = service.repository.DeleteFinalScoreByMethodIDRepository(methodID)
-	if err != nil {
-		response := map[string]string{"error": "gagal menghapus semua data nilai akhir"}
-		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+	if _, busy := running.LoadOrStore(methodID, true); busy {
+		response := map[string]string{"error": fmt.Sprintf("perhitungan metode %s sedang berjalan", getMethod.Name)}
+		helpers.ResponseJSON(ctx, http.StatusConflict, response)
 		return
 	}
+	defer running.Delete(methodID)
 
-	response := map[string]string{"message": "Berhasil membuat laporan dan memasukkan data ke detail laporan"}
-	helpers.ResponseJSON(ctx, http.StatusOK, response)
-}
-
-func (service *scoreService) utilityScoreSMART(methodID int) error {
-	criteriaList, err := service.criteriaRepository.GetAllCriteriaRepository()
-	if err != nil {
-		return fmt.Errorf("gagal mengambil data kriteria: %v", err)
+	// metode sumber tidak boleh dihitung ulang selama konsensus membaca hasilnya
+	for _, sourceID := range request.SourceMethodIDs {
+		if _, busy := running.LoadOrStore(sourceID, true); busy {
+			response := map[string]string{"error": fmt.Sprintf("perhitungan metode sumber ID:%d sedang berjalan", sourceID)}
+			helpers.ResponseJSON(ctx, http.StatusConflict, response)
+			return
+		}
+		defer running.Delete(sourceID)
 	}
 
-	scores, err := service.criteriaScoreRepository.GetAllCriteriaScoreRepository()
+	startTime := time.Now()
+
+	productIDs, sourceScores, sourceRuns, statusCode, err := service.consensusSources(request.SourceMethodIDs)
 	if err != nil {
-		return fmt.Errorf("gagal mengambil data nilai kriteria: %v", err)
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
 	}
 
-	if len(scores) == 0 {
-		return fmt.Errorf("data nilai kriteria masih kosong")
+	// kriteria dan periode mengikuti riwayat perhitungan metode sumber pertama
+	run := &calculation_run.CalculationRun{
+		MethodID:     methodID,
+		PeriodID:     sourceRuns[0].PeriodID,
+		Algorithm:    consensus.Name(),
+		UserID:       ctx.GetInt("user_id"),
+		Criteria:     sourceRuns[0].Criteria,
+		ProductCount: len(productIDs),
+		CreatedAt:    startTime,
+		UpdatedAt:    startTime,
 	}
 
-	criteriaTypes := make(map[int]string)
-	for _, criterion := range criteriaList {
-		criteriaTypes[criterion.ID] = criterion.Type
+	// metode konsensus tidak memiliki nilai per kriteria, hanya skor akhir
+	matrix := ranking.Matrix{ProductIDs: productIDs}
+	result := ranking.Result{Scores: consensus.Combine(sourceScores)}
+
+	err = service.saveResult(ctx.Request.Context(), run, matrix, result, nil)
+	if errors.Is(err, ErrMethodLocked) {
+		response := map[string]string{"error": fmt.Sprintf("perhitungan metode %s sedang berjalan", getMethod.Name)}
+		helpers.ResponseJSON(ctx, http.StatusConflict, response)
+		return
+	}
+	if err != nil {
+		response := map[string]interface{}{
+			"error":   err.Error(),
+			"process": fmt.Sprintf("Penyimpanan hasil perhitungan %s", getMethod.Name),
+		}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
 	}
 
-	// Membuat map untuk score berdasarkan kriteria
-	scoreByCriteria := make(map[int][]float64)
-	for _, score := range scores {
-		scoreByCriteria[score.CriteriaID] = append(scoreByCriteria[score.CriteriaID], score.Score)
+	processingTime := time.Since(startTime)
+
+	response := map[string]interface{}{
+		"message":         fmt.Sprintf("Perhitungan konsensus %s berhasil", getMethod.Name),
+		"processingTime":  processingTime.String(),
+		"runID":           run.ID,
+		"periodID":        run.PeriodID,
+		"sourceMethodIDs": request.SourceMethodIDs,
 	}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
 
-	for _, criteria := range criteriaList {
-		scoreValues := scoreByCriteria[criteria.ID]
-		if len(scoreValues) == 0 {
-			continue
+// consensusSources mengambil skor akhir dan riwayat perhitungan setiap metode sumber dengan urutan produk
+// yang sama, semua metode sumber harus sudah dihitung untuk kumpulan produk dan periode yang sama
+func (service *scoreService) consensusSources(sourceMethodIDs []int) (productIDs []int, scores [][]float64, runs []calculation_run.CalculationRun, statusCode int, err error) {
+	for s, sourceID := range sourceMethodIDs {
+		sourceMethod, err := service.methodRepository.GetMethodByIdRepository(sourceID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, nil, http.StatusNotFound, fmt.Errorf("Metode dengan ID:%d tidak ditemukan", sourceID)
+			}
+			return nil, nil, nil, http.StatusInternalServerError, err
 		}
 
-		minNorm := scoreValues[0]
-		maxNorm := scoreValues[0]
-		for _, value := range scoreValues {
-			if value < minNorm {
-				minNorm = value
-			}
-			if value > maxNorm {
-				maxNorm = value
-			}
+		if _, ok := ranking.GetConsensus(sourceMethod.Algorithm); ok {
+			return nil, nil, nil, http.StatusBadRequest, fmt.Errorf("metode %s adalah metode konsensus dan tidak dapat menjadi sumber", sourceMethod.Name)
 		}
 
-		// Menghitung berdasarkan tipe kriteria
-		for _, score := range scores {
-			if score.CriteriaID == criteria.ID {
-				var scoreOne float64
-
-				if maxNorm != minNorm {
-					if strings.EqualFold(criteriaTypes[criteria.ID], "Benefit") {
-						scoreOne = (score.Score - minNorm) / (maxNorm - minNorm)
-					} else if strings.EqualFold(criteriaTypes[criteria.ID], "Cost") {
-						scoreOne = (maxNorm - score.Score) / (maxNorm - minNorm)
-					}
-				}
-
-				newScore := &Score{
-					ProductID:  score.ProductID,
-					CriteriaID: score.CriteriaID,
-					ScoreOne:   scoreOne,
-					MethodID:   methodID,
-					CreatedAt:  score.CreatedAt,
-					UpdatedAt:  score.UpdatedAt,
-				}
-
-				err = service.repository.CreateScoreRepository(newScore)
-				if err != nil {
-					return fmt.Errorf("gagal update score: %v", err)
-				}
+		// sumber dibaca dari riwayat perhitungan terakhir, nilai akhirnya diambil dari final_scores atau dari
+		// detail laporan jika hasil perhitungan tersebut sudah dijadikan laporan
+		run, err := service.repository.GetLatestCalculationRunByMethodIDRepository(sourceID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, nil, http.StatusBadRequest, fmt.Errorf("metode %s belum memiliki hasil perhitungan", sourceMethod.Name)
 			}
+			return nil, nil, nil, http.StatusInternalServerError, err
 		}
-	}
 
-	return nil
-}
-
-func (service *scoreService) normalizeScoreMOORA(methodID int) error {
-	criteriaList, err := service.criteriaRepository.GetAllCriteriaRepository()
-	if err != nil {
-		return fmt.Errorf("gagal mengambil data kriteria: %v", err)
-	}
-
-	scores, err := service.criteriaScoreRepository.GetAllCriteriaScoreRepository()
-	if err != nil {
-		return fmt.Errorf("gagal mengambil data nilai kriteria: %v", err)
-	}
-
-	if len(scores) == 0 {
-		return fmt.Errorf("data nilai kriteria masih kosong")
-	}
+		scoreByProduct, err := service.runScores(run.ID)
+		if err != nil {
+			return nil, nil, nil, http.StatusInternalServerError, err
+		}
 
-	// Kelompokkan score berdasarkan criteria
-	scoreByCriteria := make(map[int][]float64)
-	for _, score := range scores {
-		scoreByCriteria[score.CriteriaID] = append(scoreByCriteria[score.CriteriaID], score.Score)
-	}
+		if len(scoreByProduct) == 0 {
+			return nil, nil, nil, http.StatusBadRequest, fmt.Errorf("metode %s belum memiliki hasil perhitungan", sourceMethod.Name)
+		}
 
-	// Lakukan normalisasi untuk setiap kriteria
-	for _, criteria := range criteriaList {
-		scoreValues := scoreByCriteria[criteria.ID]
-		if len(scoreValues) == 0 {
-			continue
+		// hasil konsensus hanya bermakna jika semua metode sumber memakai nilai kriteria periode yang sama
+		if s > 0 && run.PeriodID != runs[0].PeriodID {
+			return nil, nil, nil, http.StatusBadRequest, fmt.Errorf("metode %s dihitung pada periode ID:%d, berbeda dengan metode sumber lain (periode ID:%d)", sourceMethod.Name, run.PeriodID, runs[0].PeriodID)
+		}
+		runs = append(runs, run)
+		if s == 0 {
+			for productID := range scoreByProduct {
+				productIDs = append(productIDs, productID)
+			}
+			sort.Ints(productIDs)
 		}
 
-		// Hitung akar dari jumlah kuadrat
-		sumOfSquares := 0.0
-		for _, value := range scoreValues {
-			sumOfSquares += value * value
+		if len(scoreByProduct) != len(productIDs) {
+			return nil, nil, nil, http.StatusBadRequest, fmt.Errorf("produk pada hasil metode %s berbeda dengan metode sumber lain, hitung ulang metode tersebut", sourceMethod.Name)
 		}
-		sqrtSum := math.Sqrt(sumOfSquares)
-
-		// Normalisasi setiap score untuk kriteria ini menggunakan metode vector normalization
-		for _, score := range scores {
-			if score.CriteriaID == criteria.ID {
-				// Rumus normalisasi MOORA: rij = xij / sqrt(Σ(xij^2))
-				normalizedValue := 0.0
-				if sqrtSum != 0 {
-					normalizedValue = score.Score / sqrtSum
-				}
-
-				newScore := &Score{
-					ProductID:  score.ProductID,
-					CriteriaID: score.CriteriaID,
-					ScoreOne:   normalizedValue,
-					MethodID:   methodID,
-					CreatedAt:  score.CreatedAt,
-					UpdatedAt:  score.UpdatedAt,
-				}
-
-				err = service.repository.CreateScoreRepository(newScore)
-				if err != nil {
-					return fmt.Errorf("gagal update score: %v", err)
-				}
+
+		row := make([]float64, len(productIDs))
+		for i, productID := range productIDs {
+			value, exists := scoreByProduct[productID]
+			if !exists {
+				return nil, nil, nil, http.StatusBadRequest, fmt.Errorf("produk pada hasil metode %s berbeda dengan metode sumber lain, hitung ulang metode tersebut", sourceMethod.Name)
 			}
+			row[i] = value
 		}
+		scores = append(scores, row)
 	}
 
-	return nil
+	return productIDs, scores, runs, http.StatusOK, nil
 }
 
-func (service *scoreService) scoreOneTimesWeightByMethodID(methodID int) error {
-	scores, err := service.repository.GetAllScoreByMethodIDRepository(methodID)
+// runScores mengambil nilai akhir per produk sebuah riwayat perhitungan
+func (service *scoreService) runScores(runID int) (map[int]float64, error) {
+	finalScores, err := service.repository.GetFinalScoresByRunIdRepository(runID)
 	if err != nil {
-		return fmt.Errorf("gagal mengambil data nilai: %v", err)
+		return nil, err
 	}
 
-	if len(scores) == 0 {
-		return fmt.Errorf("data nilai masih kosong")
+	scoreByProduct := make(map[int]float64, len(finalScores))
+	for _, finalScore := range finalScores {
+		scoreByProduct[finalScore.ProductID] = finalScore.FinalScore
+	}
+	if len(scoreByProduct) > 0 {
+		return scoreByProduct, nil
 	}
 
-	criteriaList, err := service.criteriaRepository.GetAllCriteriaRepository()
+	details, err := service.repository.GetReportDetailsByRunIdRepository(runID)
 	if err != nil {
-		return fmt.Errorf("gagal mengambil data kriteria: %v", err)
+		return nil, err
 	}
-
-	// Membuat map untuk bobot kriteria
-	criteriaWeight := make(map[int]float64)
-	for _, criterion := range criteriaList {
-		criteriaWeight[criterion.ID] = criterion.Weight
+	for _, detail := range details {
+		scoreByProduct[detail.ProductID] = detail.FinalScore
 	}
+	return scoreByProduct, nil
+}
 
-	// Membuat map untuk nilai normalisasi dari kriteria
-	scoreOneByCriteria := make(map[int][]float64)
-	for _, score := range scores {
-		scoreOneByCriteria[score.CriteriaID] = append(scoreOneByCriteria[score.CriteriaID], score.ScoreOne)
+func (service *scoreService) CreateReportByMethodIDService(ctx *gin.Context) {
+	methodID, err := strconv.Atoi(ctx.Param("methodID"))
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
 	}
 
-	for _, criteria := range criteriaList {
-		scoreOne := scoreOneByCriteria[criteria.ID]
-		if len(scoreOne) == 0 {
-			continue
-		}
-
-		for _, score := range scores {
-			if score.CriteriaID == criteria.ID {
-				var scoreTwo float64
-				// Mengambil bobot kriteria
-				weight := criteria.Weight
-
-				// Mengalikan ScoreOne dengan bobot kriteria
-				scoreTwo = score.ScoreOne * weight
-
-				// Update scoreTwo
-				score.ScoreTwo = scoreTwo
-				err = service.repository.UpdateScoreByMethodIDRepository(methodID, &score)
-				if err != nil {
-					return fmt.Errorf("gagal update score two: %v", err)
-				}
-			}
-		}
+	// Mendapatkan data final score by method id
+	finalScores, err := service.finalScoreRepository.GetAllFinalScoreByMethodIDRepository(methodID)
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+		return
 	}
 
-	return nil
-}
+	if len(finalScores) == 0 {
+		response := map[string]string{"error": "data nilai akhir masih kosong"}
+		helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+		return
+	}
 
-func (service *scoreService) createFinalScoresSMART(methodID int) error {
-	scores, err := service.repository.GetAllScoreByMethodIDRepository(methodID)
+	// bobot dan tipe kriteria diambil dari riwayat perhitungan, bukan dari data kriteria saat ini
+	run, err := service.calculationRun(finalScores[0].CalculationRunID)
 	if err != nil {
-		return fmt.Errorf("gagal mengambil data nilai: %v", err)
+		response := map[string]string{"error": "gagal mengambil data kriteria perhitungan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
 	}
 
-	if len(scores) == 0 {
-		return fmt.Errorf("data nilai masih kosong")
+	products, err := service.productRepository.GetAllProductRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data produk"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
 	}
 
-	productScores := make(map[int]float64)
-
-	// Jumlahkan ScoreTwo untuk setiap produk
-	for _, score := range scores {
-		productScores[score.ProductID] += score.ScoreTwo
+	productMap := make(map[int]product.Product)
+	for _, getProduct := range products {
+		productMap[getProduct.ID] = getProduct
 	}
 
-	for productID, totalScore := range productScores {
-		finalScore := &final_score.FinalScore{
-			ProductID:  productID,
-			MethodID:   methodID,
-			FinalScore: totalScore,
+	// laporan periode menyalin angka produk pada periode tersebut, bukan data produk saat ini
+	var periodName string
+	if run.PeriodID != 0 {
+		getPeriod, err := service.periodRepository.GetPeriodByIdRepository(run.PeriodID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": "gagal mengambil data periode"}
+			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+			return
 		}
+		periodName = getPeriod.Name
 
-		err = service.repository.CreateFinalScoreByMethodIDRepository(methodID, finalScore)
+		figures, err := service.periodRepository.GetFiguresByPeriodIdRepository(run.PeriodID)
 		if err != nil {
-			return fmt.Errorf("gagal menyimpan final score: %v", err)
+			response := map[string]string{"error": "gagal mengambil data angka produk periode"}
+			helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+			return
 		}
-	}
 
-	return nil
-}
+		for _, figure := range figures {
+			getProduct, exists := productMap[figure.ProductID]
+			if !exists {
+				continue
+			}
+			getProduct.PurchaseCost = figure.PurchaseCost
+			getProduct.PriceSale = figure.PriceSale
+			getProduct.Profit = figure.Profit
+			getProduct.Stock = figure.Stock
+			getProduct.Sold = figure.Sold
+			productMap[figure.ProductID] = getProduct
+		}
+	}
 
-func (service *scoreService) createFinalScoresMOORA(methodID int) error {
-	scores, err := service.repository.GetAllScoreByMethodIDRepository(methodID)
-	if err != nil {
-		return fmt.Errorf("gagal mengambil data nilai kriteria: %v", err)
+	newReport := report.Report{
+		ReportCode:       fmt.Sprintf("LAP-%d-%d", methodID, time.Now().Unix()),
+		MethodID:         methodID,
+		CalculationRunID: finalScores[0].CalculationRunID,
+		PeriodID:         run.PeriodID,
+		PeriodName:       periodName,
+		TotalData:        len(finalScores),
+		Criteria:         run.Criteria,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+
+	details := make([]report.ReportDetail, 0, len(finalScores))
+	for _, score := range finalScores {
+		getProduct := productMap[score.ProductID]
+		details = append(details, report.ReportDetail{
+			MethodID:   methodID,
+			ProductID:  score.ProductID,
+			FinalScore: score.FinalScore,
+			Product: report.ProductSnapshot{
+				Name:         getProduct.Name,
+				PurchaseCost: getProduct.PurchaseCost,
+				PriceSale:    getProduct.PriceSale,
+				Profit:       getProduct.Profit,
+				Unit:         getProduct.Unit,
+				Stock:        getProduct.Stock,
+				Sold:         getProduct.Sold,
+			},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
 	}
 
-	criteriaList, err := service.criteriaRepository.GetAllCriteriaRepository()
+	// laporan, detail laporan dan penghapusan nilai dilakukan dalam satu transaksi
+	err = service.repository.CreateReportByMethodIDRepository(methodID, &newReport, details)
 	if err != nil {
-		return fmt.Errorf("gagal mengambil data kriteria: %v", err)
+		response := map[string]string{"error": "gagal membuat laporan dan detail laporan"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
 	}
 
-	criteriaTypes := make(map[int]string)
-	for _, criteria := range criteriaList {
-		criteriaTypes[criteria.ID] = criteria.Type
+	response := map[string]string{"message": "Berhasil membuat laporan dan memasukkan data ke detail laporan"}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+// calculationRun mengembalikan riwayat perhitungan (kriteria dan periode yang dipakai), atau kriteria saat ini
+// tanpa periode untuk nilai akhir lama yang belum memiliki riwayat perhitungan
+func (service *scoreService) calculationRun(runID int) (calculation_run.CalculationRun, error) {
+	if runID != 0 {
+		run, err := service.repository.GetCalculationRunByIdRepository(runID)
+		if err == nil {
+			return run, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return run, err
+		}
 	}
 
-	// Kelompokkan score berdasarkan produk
-	type productScore struct {
-		benefitSum float64
-		costSum    float64
+	criteriaList, err := service.criteriaRepository.GetAllCriteriaRepository()
+	if err != nil {
+		return calculation_run.CalculationRun{}, err
 	}
-	productScores := make(map[int]*productScore)
+	return calculation_run.CalculationRun{Criteria: criteria.NewSnapshot(criteriaList)}, nil
+}
 
-	// Hitung jumlah benefit dan cost berdasarkan ScoreTwo
-	for _, score := range scores {
-		if _, exists := productScores[score.ProductID]; !exists {
-			productScores[score.ProductID] = &productScore{0, 0}
-		}
+func (service *scoreService) decisionMatrix(periodID int) ([]criteria.Criteria, ranking.Matrix, error) {
+	return ranking.LoadMatrix(service.criteriaRepository, service.criteriaScoreRepository, periodID)
+}
 
-		// ScoreTwo sudah merupakan weighted normalized value
-		if strings.EqualFold(criteriaTypes[score.CriteriaID], "Benefit") {
-			productScores[score.ProductID].benefitSum += score.ScoreTwo
-		} else if strings.EqualFold(criteriaTypes[score.CriteriaID], "Cost") {
-			productScores[score.ProductID].costSum += score.ScoreTwo
+// saveResult mengganti nilai normalisasi (ScoreOne), nilai terbobot (ScoreTwo) dan skor akhir setiap produk
+// sehingga perhitungan ulang tidak menambah data ganda dan kegagalan tidak meninggalkan data setengah jadi
+func (service *scoreService) saveResult(ctx context.Context, run *calculation_run.CalculationRun, matrix ranking.Matrix, result ranking.Result, progress func(written int, total int)) error {
+	methodID := run.MethodID
+	now := time.Now()
+	scores := make([]Score, 0, len(matrix.ProductIDs)*len(matrix.Criteria))
+	finalScores := make([]final_score.FinalScore, 0, len(matrix.ProductIDs))
+	for i, productID := range matrix.ProductIDs {
+		for j, criterion := range matrix.Criteria {
+			scores = append(scores, Score{
+				ProductID:  productID,
+				CriteriaID: criterion.ID,
+				MethodID:   methodID,
+				ScoreOne:   result.Normalized[i][j],
+				ScoreTwo:   result.Weighted[i][j],
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			})
 		}
-	}
-
-	// Hitung nilai Yi untuk setiap produk
-	for productID, score := range productScores {
-		// Hitung Yi = (sum of benefit criteria) - (sum of cost criteria)
-		yi := score.benefitSum - score.costSum
 
-		finalScore := &final_score.FinalScore{
+		finalScores = append(finalScores, final_score.FinalScore{
 			ProductID:  productID,
 			MethodID:   methodID,
-			FinalScore: yi,
-			CreatedAt:  time.Now(),
-			UpdatedAt:  time.Now(),
-		}
+			FinalScore: result.Scores[i],
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
 
-		err = service.repository.CreateFinalScoreByMethodIDRepository(methodID, finalScore)
-		if err != nil {
-			return fmt.Errorf("gagal menyimpan final score: %v", err)
-		}
+	err := service.repository.ReplaceScoresByMethodIDRepository(ctx, run, scores, finalScores, progress)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan hasil perhitungan: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,183 @@
+package score
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/calculation_run"
+	"backend-profitrack/modules/ranking"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// calculationTrace menyusun tahap perhitungan beserta nama produk untuk audit
+func (service *scoreService) calculationTrace(algorithm ranking.Algorithm, matrix ranking.Matrix, result ranking.Result) (ranking.Trace, error) {
+	trace := ranking.NewTrace(algorithm, matrix, result)
+
+	products, err := service.productRepository.GetAllProductRepository()
+	if err != nil {
+		return trace, err
+	}
+
+	productNames := make(map[int]string, len(products))
+	for _, getProduct := range products {
+		productNames[getProduct.ID] = getProduct.Name
+	}
+	for i := range trace.Aggregation {
+		trace.Aggregation[i].ProductName = productNames[trace.Aggregation[i].ProductID]
+	}
+
+	return trace, nil
+}
+
+// runAggregation menyalin nilai antara tahap agregasi (mis. solusi ideal dan jarak D+/D- TOPSIS) untuk disimpan
+// pada riwayat perhitungan, kosong jika algoritma tidak mengimplementasikan ranking.AggregationTracer
+func runAggregation(algorithm ranking.Algorithm, matrix ranking.Matrix, result ranking.Result) calculation_run.Aggregation {
+	var aggregation calculation_run.Aggregation
+	tracer, ok := algorithm.(ranking.AggregationTracer)
+	if !ok {
+		return aggregation
+	}
+
+	criteriaDetails, productDetails := tracer.TraceAggregation(matrix, result.Weighted)
+	for j, values := range criteriaDetails {
+		aggregation.Criteria = append(aggregation.Criteria, calculation_run.AggregationCriterion{
+			CriteriaID: matrix.Criteria[j].ID,
+			Values:     values,
+		})
+	}
+	for i, values := range productDetails {
+		aggregation.Products = append(aggregation.Products, calculation_run.AggregationProduct{
+			ProductID: matrix.ProductIDs[i],
+			Values:    values,
+		})
+	}
+	return aggregation
+}
+
+// exportTrace menulis trace ke workbook: Kriteria, Matriks Keputusan, Normalisasi, Terbobot dan Agregasi
+func (service *scoreService) exportTrace(ctx *gin.Context, methodName string, trace ranking.Trace) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	style, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold: true,
+		},
+	})
+	if err != nil {
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membuat style"})
+		return
+	}
+
+	writeRow := func(sheet string, row int, values []interface{}, bold bool) {
+		for i, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(i+1, row)
+			f.SetCellValue(sheet, cell, value)
+			if bold {
+				f.SetCellStyle(sheet, cell, cell, style)
+			}
+		}
+	}
+
+	// Sheet kriteria berisi bobot, min/max, norma dan nilai tambahan algoritma
+	var extraKeys []string
+	seen := make(map[string]bool)
+	for _, criterion := range trace.Criteria {
+		for key := range criterion.Extra {
+			if !seen[key] {
+				seen[key] = true
+				extraKeys = append(extraKeys, key)
+			}
+		}
+	}
+	sort.Strings(extraKeys)
+
+	criteriaSheet := "Kriteria"
+	f.SetSheetName("Sheet1", criteriaSheet)
+	headers := []interface{}{"Kriteria", "Tipe", "Bobot", "Min", "Max", "Norma"}
+	for _, key := range extraKeys {
+		headers = append(headers, key)
+	}
+	writeRow(criteriaSheet, 1, headers, true)
+	for i, criterion := range trace.Criteria {
+		values := []interface{}{criterion.Name, criterion.Type, criterion.Weight, criterion.Min, criterion.Max, criterion.Norm}
+		for _, key := range extraKeys {
+			values = append(values, criterion.Extra[key])
+		}
+		writeRow(criteriaSheet, i+2, values, false)
+	}
+
+	// Matriks keputusan, normalisasi dan terbobot memakai susunan baris dan kolom yang sama
+	matrixHeaders := []interface{}{"Nama Produk"}
+	for _, criterion := range trace.Criteria {
+		matrixHeaders = append(matrixHeaders, criterion.Name)
+	}
+	matrices := []struct {
+		sheet  string
+		values [][]float64
+	}{
+		{"Matriks Keputusan", trace.DecisionMatrix},
+		{"Normalisasi", trace.Normalized},
+		{"Terbobot", trace.Weighted},
+	}
+	for _, matrix := range matrices {
+		f.NewSheet(matrix.sheet)
+		writeRow(matrix.sheet, 1, matrixHeaders, true)
+		for i, row := range matrix.values {
+			values := []interface{}{trace.Aggregation[i].ProductName}
+			for _, value := range row {
+				values = append(values, value)
+			}
+			writeRow(matrix.sheet, i+2, values, false)
+		}
+	}
+
+	// Sheet agregasi diurutkan berdasarkan peringkat
+	var detailKeys []string
+	seen = make(map[string]bool)
+	for _, product := range trace.Aggregation {
+		for key := range product.Details {
+			if !seen[key] {
+				seen[key] = true
+				detailKeys = append(detailKeys, key)
+			}
+		}
+	}
+	sort.Strings(detailKeys)
+
+	aggregation := make([]ranking.ProductTrace, len(trace.Aggregation))
+	copy(aggregation, trace.Aggregation)
+	sort.SliceStable(aggregation, func(a, b int) bool {
+		return aggregation[a].Rank < aggregation[b].Rank
+	})
+
+	aggregationSheet := "Agregasi"
+	f.NewSheet(aggregationSheet)
+	headers = []interface{}{"Peringkat", "Nama Produk"}
+	for _, key := range detailKeys {
+		headers = append(headers, key)
+	}
+	headers = append(headers, "Skor Akhir")
+	writeRow(aggregationSheet, 1, headers, true)
+	for i, product := range aggregation {
+		values := []interface{}{product.Rank, product.ProductName}
+		for _, key := range detailKeys {
+			values = append(values, product.Details[key])
+		}
+		values = append(values, product.Score)
+		writeRow(aggregationSheet, i+2, values, false)
+	}
+
+	fileName := fmt.Sprintf("trace-%s-%s.xlsx", methodName, time.Now().Format("02-01-2006"))
+
+	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+
+	if err := f.Write(ctx.Writer); err != nil {
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, gin.H{"error": "Gagal membuat file Excel"})
+		return
+	}
+}
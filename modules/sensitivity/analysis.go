@@ -0,0 +1,154 @@
+package sensitivity
+
+import (
+	"backend-profitrack/modules/ranking"
+	"math"
+)
+
+// Analyze mengubah bobot setiap kriteria dari -rangeLimit sampai +rangeLimit (relatif terhadap bobot awal)
+// dengan kelipatan step, bobot kriteria lain dinormalisasi ulang agar total tetap 1
+func Analyze(algorithm ranking.Algorithm, matrix ranking.Matrix, rangeLimit float64, step float64, top int) ResponseSensitivity {
+	baseScores := ranking.Run(algorithm, matrix).Scores
+	baseRanks := ranking.Ranks(baseScores)
+	baseTop := topProducts(matrix.ProductIDs, baseRanks, top)
+
+	result := ResponseSensitivity{
+		Algorithm:   algorithm.Name(),
+		Range:       rangeLimit,
+		Step:        step,
+		Top:         top,
+		TopProducts: baseTop,
+	}
+
+	for i, productID := range matrix.ProductIDs {
+		result.Products = append(result.Products, ResponseProductStability{
+			ProductID: productID,
+			BaseScore: baseScores[i],
+			BaseRank:  baseRanks[i],
+		})
+	}
+
+	baseWeights := matrix.Weights()
+	steps := int(math.Round(rangeLimit / step))
+	for j, criterion := range matrix.Criteria {
+		sensitivity := ResponseCriterionSensitivity{
+			CriteriaID:   criterion.ID,
+			CriteriaName: criterion.Name,
+			BaseWeight:   criterion.Weight,
+		}
+
+		minRanks := make([]int, len(matrix.ProductIDs))
+		maxRanks := make([]int, len(matrix.ProductIDs))
+		stableCount := make([]int, len(matrix.ProductIDs))
+		copy(minRanks, baseRanks)
+		copy(maxRanks, baseRanks)
+		total := 0
+
+		for s := -steps; s <= steps; s++ {
+			if s == 0 {
+				continue
+			}
+
+			change := float64(s) * step
+			weights, ok := perturb(baseWeights, j, change)
+			if !ok {
+				continue
+			}
+
+			ranks := ranking.Ranks(ranking.Run(algorithm, matrix.WithWeights(weights)).Scores)
+			currentTop := topProducts(matrix.ProductIDs, ranks, top)
+			topChanged := !equalInts(currentTop, baseTop)
+
+			sensitivity.Perturbations = append(sensitivity.Perturbations, ResponsePerturbation{
+				Change:      change,
+				Weights:     weights,
+				TopProducts: currentTop,
+				TopChanged:  topChanged,
+			})
+
+			// simpan bobot terdekat dari bobot awal yang mengubah urutan top-N
+			if topChanged {
+				weight := weights[j]
+				if change < 0 && (sensitivity.LowerWeight == nil || weight > *sensitivity.LowerWeight) {
+					sensitivity.LowerWeight = &weight
+				}
+				if change > 0 && (sensitivity.UpperWeight == nil || weight < *sensitivity.UpperWeight) {
+					sensitivity.UpperWeight = &weight
+				}
+			}
+
+			total++
+			for i, rank := range ranks {
+				if rank < minRanks[i] {
+					minRanks[i] = rank
+				}
+				if rank > maxRanks[i] {
+					maxRanks[i] = rank
+				}
+				if rank == baseRanks[i] {
+					stableCount[i]++
+				}
+			}
+		}
+
+		for i := range result.Products {
+			stableRatio := 1.0
+			if total > 0 {
+				stableRatio = float64(stableCount[i]) / float64(total)
+			}
+
+			result.Products[i].Stability = append(result.Products[i].Stability, ResponseRankStability{
+				CriteriaID:  criterion.ID,
+				MinRank:     minRanks[i],
+				MaxRank:     maxRanks[i],
+				StableRatio: stableRatio,
+			})
+		}
+
+		result.Criteria = append(result.Criteria, sensitivity)
+	}
+
+	return result
+}
+
+// perturb mengubah bobot kriteria index sebesar change (relatif) lalu menyesuaikan bobot lain secara
+// proporsional, ok bernilai false jika bobot baru keluar dari rentang (0, 1)
+func perturb(weights []float64, index int, change float64) (result []float64, ok bool) {
+	newWeight := weights[index] * (1 + change)
+	if newWeight <= 0 || newWeight >= 1 || weights[index] >= 1 {
+		return nil, false
+	}
+
+	scale := (1 - newWeight) / (1 - weights[index])
+	result = make([]float64, len(weights))
+	for j, weight := range weights {
+		if j == index {
+			result[j] = newWeight
+		} else {
+			result[j] = weight * scale
+		}
+	}
+	return result, true
+}
+
+func topProducts(productIDs []int, ranks []int, top int) []int {
+	result := make([]int, top)
+	for i, rank := range ranks {
+		if rank <= top {
+			result[rank-1] = productIDs[i]
+		}
+	}
+	return result
+}
+
+func equalInts(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
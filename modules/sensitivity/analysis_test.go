@@ -0,0 +1,46 @@
+package sensitivity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPerturb(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []float64
+		index   int
+		change  float64
+		want    []float64
+		wantOK  bool
+	}{
+		// 0.5 * 1.2 = 0.6, bobot lain dikali (1 - 0.6) / (1 - 0.5) = 0.8
+		{"naik 20%", []float64{0.5, 0.3, 0.2}, 0, 0.2, []float64{0.6, 0.24, 0.16}, true},
+		// 0.5 * 0.5 = 0.25, bobot lain dikali 0.75 / 0.5 = 1.5
+		{"turun 50%", []float64{0.5, 0.3, 0.2}, 0, -0.5, []float64{0.25, 0.45, 0.3}, true},
+		{"kriteria tengah", []float64{0.5, 0.3, 0.2}, 1, 0.5, []float64{0.5 * 0.55 / 0.7, 0.45, 0.2 * 0.55 / 0.7}, true},
+		{"bobot menjadi 0", []float64{0.5, 0.3, 0.2}, 0, -1, nil, false},
+		{"bobot menjadi 1", []float64{0.5, 0.3, 0.2}, 0, 1, nil, false},
+		{"satu-satunya kriteria", []float64{1}, 0, -0.1, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := perturb(test.weights, test.index, test.change)
+			if ok != test.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, test.wantOK)
+			}
+
+			total := 0.0
+			for j, want := range test.want {
+				total += got[j]
+				if math.Abs(got[j]-want) > 1e-9 {
+					t.Errorf("bobot %d = %v, want %v", j, got[j], want)
+				}
+			}
+			if ok && math.Abs(total-1) > 1e-9 {
+				t.Errorf("total bobot = %v, want 1", total)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package sensitivity
+
+type ResponsePerturbation struct {
+	Change      float64   `json:"change"`
+	Weights     []float64 `json:"weights"`
+	TopProducts []int     `json:"top_products"`
+	TopChanged  bool      `json:"top_changed"`
+}
+
+// ResponseCriterionSensitivity berisi hasil perubahan bobot satu kriteria. LowerWeight dan UpperWeight
+// adalah bobot terdekat dari bobot awal (ke bawah dan ke atas) yang mengubah urutan top-N, nil jika tidak pernah berubah
+type ResponseCriterionSensitivity struct {
+	CriteriaID    int                    `json:"criteria_id"`
+	CriteriaName  string                 `json:"criteria_name"`
+	BaseWeight    float64                `json:"base_weight"`
+	LowerWeight   *float64               `json:"lower_weight"`
+	UpperWeight   *float64               `json:"upper_weight"`
+	Perturbations []ResponsePerturbation `json:"perturbations"`
+}
+
+type ResponseRankStability struct {
+	CriteriaID  int     `json:"criteria_id"`
+	MinRank     int     `json:"min_rank"`
+	MaxRank     int     `json:"max_rank"`
+	StableRatio float64 `json:"stable_ratio"`
+}
+
+type ResponseProductStability struct {
+	ProductID int                     `json:"product_id"`
+	BaseScore float64                 `json:"base_score"`
+	BaseRank  int                     `json:"base_rank"`
+	Stability []ResponseRankStability `json:"stability"`
+}
+
+type ResponseSensitivity struct {
+	MethodID    int                            `json:"method_id"`
+	PeriodID    int                            `json:"period_id"`
+	Algorithm   string                         `json:"algorithm"`
+	Range       float64                        `json:"range"`
+	Step        float64                        `json:"step"`
+	Top         int                            `json:"top"`
+	TopProducts []int                          `json:"top_products"`
+	Criteria    []ResponseCriterionSensitivity `json:"criteria"`
+	Products    []ResponseProductStability     `json:"products"`
+}
@@ -0,0 +1,24 @@
+package sensitivity
+
+import (
+	"backend-profitrack/middleware"
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/criteria_score"
+	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	criteriaRepo := criteria.NewCriteriaRepository(db)
+	criteriaScoreRepo := criteria_score.NewCriteriaScoreRepository(db)
+	methodRepo := method.NewMethodRepository(db)
+	periodRepo := period.NewPeriodRepository(db)
+	service := NewSensitivityService(criteriaRepo, criteriaScoreRepo, methodRepo, periodRepo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.GET("/sensitivity/:methodID", service.AnalyzeSensitivityService)
+}
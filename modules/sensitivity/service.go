@@ -0,0 +1,110 @@
+package sensitivity
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/criteria_score"
+	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
+	"backend-profitrack/modules/ranking"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+)
+
+type Service interface {
+	AnalyzeSensitivityService(ctx *gin.Context)
+}
+
+type sensitivityService struct {
+	criteriaRepository      criteria.Repository
+	criteriaScoreRepository criteria_score.Repository
+	methodRepository        method.Repository
+	periodRepository        period.Repository
+}
+
+func NewSensitivityService(criteriaRepo criteria.Repository, criteriaScoreRepo criteria_score.Repository, methodRepo method.Repository, periodRepo period.Repository) Service {
+	return &sensitivityService{
+		criteriaRepository:      criteriaRepo,
+		criteriaScoreRepository: criteriaScoreRepo,
+		methodRepository:        methodRepo,
+		periodRepository:        periodRepo,
+	}
+}
+
+// AnalyzeSensitivityService menghitung ulang skor akhir di memori untuk setiap perubahan bobot,
+// query: range (default 0.5 = ±50%), step (default 0.1), top (default 3) dan period_id (nilai kriteria periode)
+func (service *sensitivityService) AnalyzeSensitivityService(ctx *gin.Context) {
+	methodID, err := strconv.Atoi(ctx.Param("methodID"))
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	rangeLimit, err := strconv.ParseFloat(ctx.DefaultQuery("range", "0.5"), 64)
+	if err != nil || rangeLimit <= 0 || rangeLimit > 1 {
+		response := map[string]string{"error": "range harus lebih dari 0 dan maksimal 1"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	step, err := strconv.ParseFloat(ctx.DefaultQuery("step", "0.1"), 64)
+	if err != nil || step <= 0 || step > rangeLimit || rangeLimit/step > 100 {
+		response := map[string]string{"error": "step harus lebih dari 0, tidak melebihi range dan maksimal 100 langkah"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	top, err := strconv.Atoi(ctx.DefaultQuery("top", "3"))
+	if err != nil || top < 1 {
+		response := map[string]string{"error": "top harus lebih dari 0"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	getPeriod, statusCode, err := period.Resolve(service.periodRepository, ctx.Query("period_id"))
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	getMethod, err := service.methodRepository.GetMethodByIdRepository(methodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Metode dengan ID:%d tidak ditemukan", methodID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	algorithm, ok := ranking.Get(getMethod.Algorithm)
+	if !ok {
+		response := map[string]string{"error": fmt.Sprintf("metode %s belum terhubung dengan algoritma perhitungan", getMethod.Name)}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	_, matrix, err := ranking.LoadMatrix(service.criteriaRepository, service.criteriaScoreRepository, getPeriod.ID)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if top > len(matrix.ProductIDs) {
+		top = len(matrix.ProductIDs)
+	}
+
+	result := Analyze(algorithm, matrix, rangeLimit, step, top)
+	result.MethodID = methodID
+	result.PeriodID = getPeriod.ID
+	helpers.ResponseJSON(ctx, http.StatusOK, result)
+}
@@ -0,0 +1,53 @@
+package simulation
+
+// RequestProductOverride mengubah satu angka produk, isi salah satu dari value (nilai baru)
+// atau percent (perubahan dalam persen, mis. 10 = naik 10%, -5 = turun 5%)
+type RequestProductOverride struct {
+	ProductID int      `json:"product_id"`
+	Field     string   `json:"field"`
+	Value     *float64 `json:"value"`
+	Percent   *float64 `json:"percent"`
+}
+
+type RequestWeightOverride struct {
+	CriteriaID int     `json:"criteria_id"`
+	Weight     float64 `json:"weight"`
+}
+
+// RequestSimulation: query period_id memakai angka produk periode tersebut sebagai data awal simulasi
+type RequestSimulation struct {
+	MethodID int                      `json:"method_id"`
+	Products []RequestProductOverride `json:"products"`
+	Criteria []RequestWeightOverride  `json:"criteria"`
+}
+
+type ResponseSimulatedCriteria struct {
+	ID            int     `json:"id"`
+	Name          string  `json:"name"`
+	Type          string  `json:"type"`
+	CurrentWeight float64 `json:"current_weight"`
+	Weight        float64 `json:"weight"`
+}
+
+// ResponseSimulatedProduct menyandingkan hasil simulasi dengan nilai akhir tersimpan, Current* bernilai null
+// jika produk belum memiliki nilai akhir pada metode tersebut atau nilai akhirnya dihitung pada periode lain
+type ResponseSimulatedProduct struct {
+	ProductID      int                `json:"product_id"`
+	ProductName    string             `json:"product_name"`
+	Figures        map[string]float64 `json:"figures"`
+	Overridden     bool               `json:"overridden"`
+	CurrentScore   *float64           `json:"current_score"`
+	CurrentRank    *int               `json:"current_rank"`
+	SimulatedScore float64            `json:"simulated_score"`
+	SimulatedRank  int                `json:"simulated_rank"`
+	RankDelta      *int               `json:"rank_delta"`
+}
+
+type ResponseSimulation struct {
+	MethodID   int                         `json:"method_id"`
+	MethodName string                      `json:"method_name"`
+	PeriodID   int                         `json:"period_id"`
+	Algorithm  string                      `json:"algorithm"`
+	Criteria   []ResponseSimulatedCriteria `json:"criteria"`
+	Products   []ResponseSimulatedProduct  `json:"products"`
+}
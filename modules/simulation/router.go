@@ -0,0 +1,28 @@
+package simulation
+
+import (
+	"backend-profitrack/middleware"
+	"backend-profitrack/modules/calculation_run"
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/final_score"
+	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
+	"backend-profitrack/modules/product"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	productRepo := product.NewProductRepository(db)
+	criteriaRepo := criteria.NewCriteriaRepository(db)
+	methodRepo := method.NewMethodRepository(db)
+	finalScoreRepo := final_score.NewFinalScoreRepository(db)
+	periodRepo := period.NewPeriodRepository(db)
+	calculationRunRepo := calculation_run.NewCalculationRunRepository(db)
+	service := NewSimulationService(productRepo, criteriaRepo, methodRepo, finalScoreRepo, periodRepo, calculationRunRepo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.POST("/simulations", service.SimulateService)
+}
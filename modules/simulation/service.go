@@ -0,0 +1,378 @@
+package simulation
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/calculation_run"
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/criteria_score"
+	"backend-profitrack/modules/final_score"
+	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
+	"backend-profitrack/modules/product"
+	"backend-profitrack/modules/ranking"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"sort"
+)
+
+type Service interface {
+	SimulateService(ctx *gin.Context)
+}
+
+type simulationService struct {
+	productRepository        product.Repository
+	criteriaRepository       criteria.Repository
+	methodRepository         method.Repository
+	finalScoreRepository     final_score.Repository
+	periodRepository         period.Repository
+	calculationRunRepository calculation_run.Repository
+}
+
+func NewSimulationService(productRepo product.Repository, criteriaRepo criteria.Repository, methodRepo method.Repository, finalScoreRepo final_score.Repository, periodRepo period.Repository, calculationRunRepo calculation_run.Repository) Service {
+	return &simulationService{
+		productRepository:        productRepo,
+		criteriaRepository:       criteriaRepo,
+		methodRepository:         methodRepo,
+		finalScoreRepository:     finalScoreRepo,
+		periodRepository:         periodRepo,
+		calculationRunRepository: calculationRunRepo,
+	}
+}
+
+// SimulateService menghitung ulang nilai kriteria dan skor akhir di memori dengan data produk dan bobot
+// yang diubah, tidak ada data yang disimpan
+func (service *simulationService) SimulateService(ctx *gin.Context) {
+	var request RequestSimulation
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	getPeriod, statusCode, err := period.Resolve(service.periodRepository, ctx.Query("period_id"))
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	getMethod, err := service.methodRepository.GetMethodByIdRepository(request.MethodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Metode dengan ID:%d tidak ditemukan", request.MethodID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	algorithm, ok := ranking.Get(getMethod.Algorithm)
+	if !ok {
+		response := map[string]string{"error": fmt.Sprintf("metode %s belum terhubung dengan algoritma perhitungan", getMethod.Name)}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	products, err := service.productRepository.GetAllProductRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data produk"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(products) == 0 {
+		response := map[string]string{"error": "data produk masih kosong"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	products, figures, statusCode, err := service.baseFigures(getPeriod, products)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	overridden, statusCode, err := applyProductOverrides(figures, request.Products)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	currentCriteria, err := service.criteriaRepository.GetAllCriteriaRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data kriteria"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	simulatedCriteria, statusCode, err := applyWeightOverrides(currentCriteria, request.Criteria)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	// nilai kriteria dihitung ulang dari rumus setiap kriteria, sama seperti CreateAllCriteriaScoreService
+	evaluators := make([]criteria.Evaluator, len(simulatedCriteria))
+	for i, kriteria := range simulatedCriteria {
+		evaluators[i], err = kriteria.Evaluator()
+		if err != nil {
+			response := map[string]string{"error": err.Error()}
+			helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	var scores []criteria_score.CriteriaScore
+	for _, getProduct := range products {
+		variables := figures[getProduct.ID].Variables()
+		for i, kriteria := range simulatedCriteria {
+			nilai, err := evaluators[i].Evaluate(variables)
+			if err != nil {
+				response := map[string]string{"error": fmt.Sprintf("produk %s: %v", getProduct.Name, err)}
+				helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+				return
+			}
+
+			scores = append(scores, criteria_score.CriteriaScore{
+				ProductID:  getProduct.ID,
+				CriteriaID: kriteria.ID,
+				Score:      nilai,
+			})
+		}
+	}
+
+	matrix, err := ranking.BuildMatrix(simulatedCriteria, scores)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	result := ranking.Run(algorithm, matrix)
+
+	finalScores, err := service.currentFinalScores(getMethod.ID, getPeriod.ID)
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data nilai akhir"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := ResponseSimulation{
+		MethodID:   getMethod.ID,
+		MethodName: getMethod.Name,
+		PeriodID:   getPeriod.ID,
+		Algorithm:  algorithm.Name(),
+		Criteria:   make([]ResponseSimulatedCriteria, len(simulatedCriteria)),
+		Products:   compareWithCurrent(products, figures, overridden, matrix, result, finalScores),
+	}
+	for i, kriteria := range simulatedCriteria {
+		response.Criteria[i] = ResponseSimulatedCriteria{
+			ID:            kriteria.ID,
+			Name:          kriteria.Name,
+			Type:          kriteria.Type,
+			CurrentWeight: currentCriteria[i].Weight,
+			Weight:        kriteria.Weight,
+		}
+	}
+
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+// baseFigures mengembalikan produk beserta angka awal simulasi, tanpa periode dari data produk saat ini dan
+// dengan periode dari angka produk periode tersebut (produk tanpa angka pada periode dilewati)
+func (service *simulationService) baseFigures(getPeriod period.Period, products []product.Product) ([]product.Product, map[int]product.Figures, int, error) {
+	figures := make(map[int]product.Figures, len(products))
+	if getPeriod.ID == 0 {
+		for _, getProduct := range products {
+			figures[getProduct.ID] = getProduct.Figures()
+		}
+		return products, figures, http.StatusOK, nil
+	}
+
+	periodFigures, err := service.periodRepository.GetFiguresByPeriodIdRepository(getPeriod.ID)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, errors.New("gagal mengambil angka produk periode")
+	}
+	for _, figure := range periodFigures {
+		figures[figure.ProductID] = figure.Figures()
+	}
+
+	result := make([]product.Product, 0, len(figures))
+	for _, getProduct := range products {
+		if _, exists := figures[getProduct.ID]; exists {
+			result = append(result, getProduct)
+		}
+	}
+	if len(result) == 0 {
+		return nil, nil, http.StatusBadRequest, fmt.Errorf("periode %s belum memiliki angka produk", getPeriod.Name)
+	}
+	return result, figures, http.StatusOK, nil
+}
+
+// currentFinalScores mengambil nilai akhir tersimpan metode sebagai pembanding, nilai akhir yang dihitung
+// pada periode lain tidak dipakai
+func (service *simulationService) currentFinalScores(methodID int, periodID int) ([]final_score.FinalScore, error) {
+	finalScores, err := service.finalScoreRepository.GetAllFinalScoreByMethodIDRepository(methodID)
+	if err != nil || len(finalScores) == 0 {
+		return finalScores, err
+	}
+
+	runPeriodID := 0
+	run, err := service.calculationRunRepository.GetCalculationRunByIdRepository(finalScores[0].CalculationRunID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		runPeriodID = run.PeriodID
+	}
+
+	if runPeriodID != periodID {
+		return nil, nil
+	}
+	return finalScores, nil
+}
+
+// applyProductOverrides mengubah angka produk pada figures, keuntungan dihitung ulang dari harga jual -
+// harga beli dan pendapatan dari harga jual * terjual kecuali ikut diubah secara langsung
+func applyProductOverrides(figures map[int]product.Figures, overrides []RequestProductOverride) (map[int]bool, int, error) {
+	overridden := make(map[int]bool)
+	profitOverridden := make(map[int]bool)
+	revenueOverridden := make(map[int]bool)
+	for _, override := range overrides {
+		current, exists := figures[override.ProductID]
+		if !exists {
+			return nil, http.StatusNotFound, fmt.Errorf("Produk dengan ID:%d tidak ditemukan", override.ProductID)
+		}
+
+		if _, exists := product.FormulaVariables[override.Field]; !exists {
+			return nil, http.StatusBadRequest, fmt.Errorf("field %q tidak dapat diubah", override.Field)
+		}
+
+		if (override.Value == nil) == (override.Percent == nil) {
+			return nil, http.StatusBadRequest, fmt.Errorf("isi salah satu value atau percent untuk field %s produk ID:%d", override.Field, override.ProductID)
+		}
+
+		value := product.FormulaVariables[override.Field](current)
+		if override.Value != nil {
+			value = *override.Value
+		} else {
+			value = value * (1 + *override.Percent/100)
+		}
+
+		switch override.Field {
+		case "purchase_cost":
+			current.PurchaseCost = value
+		case "price_sale":
+			current.PriceSale = value
+		case "profit":
+			current.Profit = value
+			profitOverridden[override.ProductID] = true
+		case "stock":
+			current.Stock = value
+		case "sold":
+			current.Sold = value
+		case "revenue":
+			current.Revenue = value
+			revenueOverridden[override.ProductID] = true
+		}
+
+		figures[override.ProductID] = current
+		overridden[override.ProductID] = true
+	}
+
+	for productID := range overridden {
+		current := figures[productID]
+		if !profitOverridden[productID] {
+			current.Profit = current.PriceSale - current.PurchaseCost
+		}
+		if !revenueOverridden[productID] {
+			current.Revenue = current.PriceSale * current.Sold
+		}
+		figures[productID] = current
+	}
+
+	return overridden, http.StatusOK, nil
+}
+
+// applyWeightOverrides mengganti bobot kriteria lalu selalu menormalkan total bobot menjadi 1,
+// sehingga cukup mengubah satu bobot tanpa menyesuaikan bobot lainnya
+func applyWeightOverrides(criteriaList []criteria.Criteria, overrides []RequestWeightOverride) ([]criteria.Criteria, int, error) {
+	result := make([]criteria.Criteria, len(criteriaList))
+	copy(result, criteriaList)
+
+	indexes := make(map[int]int, len(result))
+	for i, kriteria := range result {
+		indexes[kriteria.ID] = i
+	}
+
+	for _, override := range overrides {
+		index, exists := indexes[override.CriteriaID]
+		if !exists {
+			return nil, http.StatusNotFound, fmt.Errorf("Kriteria dengan ID:%d tidak ditemukan", override.CriteriaID)
+		}
+
+		if override.Weight < 0 {
+			return nil, http.StatusBadRequest, fmt.Errorf("bobot kriteria %s tidak boleh negatif", result[index].Name)
+		}
+		result[index].Weight = override.Weight
+	}
+
+	result, _, err := criteria.ApplyWeightPolicy(result, criteria.WeightPolicyNormalize)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return result, http.StatusOK, nil
+}
+
+// compareWithCurrent menyandingkan peringkat simulasi dengan peringkat dari nilai akhir tersimpan
+func compareWithCurrent(products []product.Product, figures map[int]product.Figures, overridden map[int]bool, matrix ranking.Matrix, result ranking.Result, finalScores []final_score.FinalScore) []ResponseSimulatedProduct {
+	productNames := make(map[int]string, len(products))
+	for _, getProduct := range products {
+		productNames[getProduct.ID] = getProduct.Name
+	}
+
+	currentScores := make([]float64, len(finalScores))
+	for i, finalScore := range finalScores {
+		currentScores[i] = finalScore.FinalScore
+	}
+	currentRanks := ranking.Ranks(currentScores)
+
+	currentIndex := make(map[int]int, len(finalScores))
+	for i, finalScore := range finalScores {
+		currentIndex[finalScore.ProductID] = i
+	}
+
+	simulatedRanks := ranking.Ranks(result.Scores)
+	rows := make([]ResponseSimulatedProduct, len(matrix.ProductIDs))
+	for i, productID := range matrix.ProductIDs {
+		rows[i] = ResponseSimulatedProduct{
+			ProductID:      productID,
+			ProductName:    productNames[productID],
+			Figures:        figures[productID].Variables(),
+			Overridden:     overridden[productID],
+			SimulatedScore: result.Scores[i],
+			SimulatedRank:  simulatedRanks[i],
+		}
+
+		if index, exists := currentIndex[productID]; exists {
+			currentScore, currentRank := finalScores[index].FinalScore, currentRanks[index]
+			delta := simulatedRanks[i] - currentRank
+			rows[i].CurrentScore = &currentScore
+			rows[i].CurrentRank = &currentRank
+			rows[i].RankDelta = &delta
+		}
+	}
+
+	sort.SliceStable(rows, func(a, b int) bool {
+		return rows[a].SimulatedRank < rows[b].SimulatedRank
+	})
+	return rows
+}
@@ -0,0 +1,112 @@
+package smaa
+
+import (
+	"backend-profitrack/modules/ranking"
+	"math"
+	"math/rand"
+)
+
+// Sampler menghasilkan satu vektor bobot dengan total 1
+type Sampler func(random *rand.Rand) []float64
+
+// SimplexSampler mengambil bobot seragam pada simplex (distribusi Dirichlet(1,...,1))
+func SimplexSampler(criteriaCount int) Sampler {
+	return func(random *rand.Rand) []float64 {
+		weights := make([]float64, criteriaCount)
+		var total float64
+		for j := range weights {
+			weights[j] = random.ExpFloat64()
+			total += weights[j]
+		}
+		for j := range weights {
+			weights[j] /= total
+		}
+		return weights
+	}
+}
+
+// IntervalSampler mengambil bobot seragam di antara minWeights dan maxWeights lalu menormalkannya menjadi total 1
+func IntervalSampler(minWeights []float64, maxWeights []float64) Sampler {
+	return func(random *rand.Rand) []float64 {
+		weights := make([]float64, len(minWeights))
+		var total float64
+		for j := range weights {
+			weights[j] = minWeights[j] + random.Float64()*(maxWeights[j]-minWeights[j])
+			total += weights[j]
+		}
+		if total == 0 {
+			return SimplexSampler(len(weights))(random)
+		}
+		for j := range weights {
+			weights[j] /= total
+		}
+		return weights
+	}
+}
+
+// Analyze menjalankan algoritma untuk setiap sampel bobot dan menghitung indeks penerimaan peringkat 1..topRanks,
+// normalisasi tidak bergantung pada bobot sehingga cukup dihitung sekali
+func Analyze(algorithm ranking.Algorithm, matrix ranking.Matrix, sampler Sampler, samples int, topRanks int, seed int64) []ResponseProductAcceptability {
+	random := rand.New(rand.NewSource(seed))
+	productCount, criteriaCount := len(matrix.ProductIDs), len(matrix.Criteria)
+	topRanks = min(topRanks, productCount)
+	normalized := algorithm.Normalize(matrix)
+
+	rankCounts := make([][]int, productCount)
+	centralSums := make([][]float64, productCount)
+	for i := range rankCounts {
+		rankCounts[i] = make([]int, topRanks)
+		centralSums[i] = make([]float64, criteriaCount)
+	}
+
+	for s := 0; s < samples; s++ {
+		weights := sampler(random)
+		sample := matrix.WithWeights(weights)
+		scores := algorithm.Aggregate(sample, algorithm.Weight(sample, normalized))
+
+		for i, rank := range ranking.Ranks(scores) {
+			if rank > topRanks {
+				continue
+			}
+			rankCounts[i][rank-1]++
+			if rank == 1 {
+				for j, weight := range weights {
+					centralSums[i][j] += weight
+				}
+			}
+		}
+	}
+
+	baseRanks := ranking.Ranks(ranking.Run(algorithm, matrix).Scores)
+	result := make([]ResponseProductAcceptability, productCount)
+	for i, productID := range matrix.ProductIDs {
+		result[i] = ResponseProductAcceptability{
+			ProductID:     productID,
+			BaseRank:      baseRanks[i],
+			Acceptability: make([]float64, topRanks),
+		}
+
+		topCount := 0
+		for r, count := range rankCounts[i] {
+			result[i].Acceptability[r] = float64(count) / float64(samples)
+			topCount += count
+		}
+		result[i].OtherAcceptability = float64(samples-topCount) / float64(samples)
+
+		if topRanks == 0 {
+			continue
+		}
+		if firstCount := rankCounts[i][0]; firstCount > 0 {
+			result[i].CentralWeights = make([]float64, criteriaCount)
+			for j, total := range centralSums[i] {
+				result[i].CentralWeights[j] = total / float64(firstCount)
+			}
+		}
+	}
+	return result
+}
+
+// defaultInterval adalah bobot ± spread relatif terhadap bobot tersebut, batas bawah tidak kurang dari 0
+func defaultInterval(weight float64, spread float64) (float64, float64) {
+	return math.Max(0, weight*(1-spread)), weight * (1 + spread)
+}
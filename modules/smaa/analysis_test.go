@@ -0,0 +1,86 @@
+package smaa
+
+import (
+	"backend-profitrack/modules/ranking"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func testMatrix() ranking.Matrix {
+	return ranking.Matrix{
+		ProductIDs: []int{1, 2, 3, 4, 5},
+		Criteria: []ranking.Criterion{
+			{ID: 1, Name: "ROI", Weight: 0.3, Type: "Benefit"},
+			{ID: 2, Name: "NPM", Weight: 0.4, Type: "Benefit"},
+			{ID: 3, Name: "Rasio Efisiensi", Weight: 0.3, Type: "Cost"},
+		},
+		Values: [][]float64{
+			{0.12, 0.20, 0.80},
+			{0.30, 0.10, 0.90},
+			{0.05, 0.35, 0.65},
+			{0.22, 0.25, 0.75},
+			{0.18, 0.15, 0.70},
+		},
+	}
+}
+
+func TestAnalyzeReproducible(t *testing.T) {
+	matrix := testMatrix()
+	samplers := map[string]Sampler{
+		"simplex":  SimplexSampler(len(matrix.Criteria)),
+		"interval": IntervalSampler([]float64{0.2, 0.3, 0.2}, []float64{0.4, 0.5, 0.4}),
+	}
+
+	for _, name := range []string{"SMART", "MOORA", "TOPSIS"} {
+		algorithm, ok := ranking.Get(name)
+		if !ok {
+			t.Fatalf("algoritma %s tidak terdaftar", name)
+		}
+
+		for samplerName, sampler := range samplers {
+			t.Run(name+" "+samplerName, func(t *testing.T) {
+				first := Analyze(algorithm, matrix, sampler, 500, len(matrix.ProductIDs), 42)
+				second := Analyze(algorithm, matrix, sampler, 500, len(matrix.ProductIDs), 42)
+				if !reflect.DeepEqual(first, second) {
+					t.Fatal("seed yang sama menghasilkan nilai penerimaan yang berbeda")
+				}
+			})
+		}
+	}
+}
+
+func TestAnalyzeAcceptabilitySumsToOne(t *testing.T) {
+	matrix := testMatrix()
+	algorithm, _ := ranking.Get("TOPSIS")
+	sampler := SimplexSampler(len(matrix.Criteria))
+
+	tests := []struct {
+		name     string
+		topRanks int
+		want     int
+	}{
+		{"semua peringkat", 5, 5},
+		{"peringkat teratas saja", 2, 2},
+		{"melebihi jumlah produk", 50, 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Analyze(algorithm, matrix, sampler, 1000, test.topRanks, 7)
+			for _, product := range result {
+				if len(product.Acceptability) != test.want {
+					t.Fatalf("produk %d memiliki %d peringkat, want %d", product.ProductID, len(product.Acceptability), test.want)
+				}
+
+				total := product.OtherAcceptability
+				for _, value := range product.Acceptability {
+					total += value
+				}
+				if math.Abs(total-1) > 1e-9 {
+					t.Errorf("total penerimaan produk %d = %v, want 1", product.ProductID, total)
+				}
+			}
+		})
+	}
+}
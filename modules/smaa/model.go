@@ -0,0 +1,67 @@
+package smaa
+
+// Cara pengambilan sampel bobot
+const (
+	SamplingSimplex  = "simplex"
+	SamplingInterval = "interval"
+)
+
+const (
+	defaultSamples = 1000
+	maxSamples     = 100000
+	defaultSpread  = 0.2
+	// hanya peluang peringkat 1..top_ranks yang disimpan agar memori tidak tumbuh kuadratik terhadap jumlah produk
+	defaultTopRanks = 10
+	maxTopRanks     = 100
+)
+
+// RequestInterval adalah batas bobot sebuah kriteria sebelum bobot sampel dinormalisasi
+type RequestInterval struct {
+	CriteriaID int     `json:"criteria_id"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+}
+
+// RequestSMAA: sampling simplex mengambil bobot acak seragam dengan total 1, sampling interval mengambil bobot
+// di antara Min-Max setiap kriteria, kriteria tanpa interval memakai bobot saat ini ± spread (default 20%).
+// TopRanks adalah jumlah peringkat teratas yang dihitung peluangnya (default 10)
+type RequestSMAA struct {
+	Samples   int               `json:"samples"`
+	TopRanks  int               `json:"top_ranks"`
+	Seed      *int64            `json:"seed"`
+	Sampling  string            `json:"sampling"`
+	Spread    *float64          `json:"spread"`
+	Intervals []RequestInterval `json:"intervals"`
+}
+
+type ResponseCriteriaInterval struct {
+	CriteriaID   int     `json:"criteria_id"`
+	CriteriaName string  `json:"criteria_name"`
+	Weight       float64 `json:"weight"`
+	Min          float64 `json:"min"`
+	Max          float64 `json:"max"`
+}
+
+// ResponseProductAcceptability: Acceptability[r] adalah peluang produk berada di peringkat r+1 untuk peringkat
+// teratas, OtherAcceptability adalah peluang berada di bawah peringkat tersebut. CentralWeights adalah
+// rata-rata bobot ketika produk berada di peringkat 1 (null jika tidak pernah)
+type ResponseProductAcceptability struct {
+	ProductID          int       `json:"product_id"`
+	ProductName        string    `json:"product_name"`
+	BaseRank           int       `json:"base_rank"`
+	Acceptability      []float64 `json:"acceptability"`
+	OtherAcceptability float64   `json:"other_acceptability"`
+	CentralWeights     []float64 `json:"central_weights"`
+}
+
+type ResponseSMAA struct {
+	MethodID  int                            `json:"method_id"`
+	PeriodID  int                            `json:"period_id"`
+	Algorithm string                         `json:"algorithm"`
+	Sampling  string                         `json:"sampling"`
+	Samples   int                            `json:"samples"`
+	TopRanks  int                            `json:"top_ranks"`
+	Seed      int64                          `json:"seed"`
+	Criteria  []ResponseCriteriaInterval     `json:"criteria"`
+	Products  []ResponseProductAcceptability `json:"products"`
+}
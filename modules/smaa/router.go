@@ -0,0 +1,26 @@
+package smaa
+
+import (
+	"backend-profitrack/middleware"
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/criteria_score"
+	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
+	"backend-profitrack/modules/product"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Initiator(router *gin.Engine, db *gorm.DB) {
+	criteriaRepo := criteria.NewCriteriaRepository(db)
+	criteriaScoreRepo := criteria_score.NewCriteriaScoreRepository(db)
+	methodRepo := method.NewMethodRepository(db)
+	productRepo := product.NewProductRepository(db)
+	periodRepo := period.NewPeriodRepository(db)
+	service := NewSMAAService(criteriaRepo, criteriaScoreRepo, methodRepo, productRepo, periodRepo)
+
+	api := router.Group("/api")
+	api.Use(middleware.LoggingMiddleware())
+	api.Use(middleware.JWTMiddleware())
+	api.POST("/smaa/:methodID", service.AnalyzeSMAAService)
+}
@@ -0,0 +1,220 @@
+package smaa
+
+import (
+	"backend-profitrack/helpers"
+	"backend-profitrack/modules/criteria"
+	"backend-profitrack/modules/criteria_score"
+	"backend-profitrack/modules/method"
+	"backend-profitrack/modules/period"
+	"backend-profitrack/modules/product"
+	"backend-profitrack/modules/ranking"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type Service interface {
+	AnalyzeSMAAService(ctx *gin.Context)
+}
+
+type smaaService struct {
+	criteriaRepository      criteria.Repository
+	criteriaScoreRepository criteria_score.Repository
+	methodRepository        method.Repository
+	productRepository       product.Repository
+	periodRepository        period.Repository
+}
+
+func NewSMAAService(criteriaRepo criteria.Repository, criteriaScoreRepo criteria_score.Repository, methodRepo method.Repository, productRepo product.Repository, periodRepo period.Repository) Service {
+	return &smaaService{
+		criteriaRepository:      criteriaRepo,
+		criteriaScoreRepository: criteriaScoreRepo,
+		methodRepository:        methodRepo,
+		productRepository:       productRepo,
+		periodRepository:        periodRepo,
+	}
+}
+
+// AnalyzeSMAAService menghitung peluang setiap produk berada di setiap peringkat ketika bobot tidak pasti,
+// seed yang sama dengan data yang sama selalu menghasilkan nilai yang sama. Query period_id memakai nilai kriteria periode
+func (service *smaaService) AnalyzeSMAAService(ctx *gin.Context) {
+	methodID, err := strconv.Atoi(ctx.Param("methodID"))
+	if err != nil {
+		response := map[string]string{"error": "ID tidak sesuai"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	var request RequestSMAA
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response := map[string]string{"error": "failed to read json"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if request.Samples == 0 {
+		request.Samples = defaultSamples
+	}
+	if request.Samples < 0 || request.Samples > maxSamples {
+		response := map[string]string{"error": fmt.Sprintf("samples harus antara 1 dan %d", maxSamples)}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if request.TopRanks == 0 {
+		request.TopRanks = defaultTopRanks
+	}
+	if request.TopRanks < 0 || request.TopRanks > maxTopRanks {
+		response := map[string]string{"error": fmt.Sprintf("top_ranks harus antara 1 dan %d", maxTopRanks)}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	if request.Sampling == "" {
+		request.Sampling = SamplingSimplex
+	}
+	if request.Sampling != SamplingSimplex && request.Sampling != SamplingInterval {
+		response := map[string]string{"error": "sampling harus simplex atau interval"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	spread := defaultSpread
+	if request.Spread != nil {
+		spread = *request.Spread
+	}
+	if spread < 0 || spread > 1 {
+		response := map[string]string{"error": "spread harus antara 0 dan 1"}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	// seed dikembalikan pada response agar hasil dapat diulang
+	seed := time.Now().UnixNano()
+	if request.Seed != nil {
+		seed = *request.Seed
+	}
+
+	getPeriod, statusCode, err := period.Resolve(service.periodRepository, ctx.Query("period_id"))
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, statusCode, response)
+		return
+	}
+
+	getMethod, err := service.methodRepository.GetMethodByIdRepository(methodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := map[string]string{"error": fmt.Sprintf("Metode dengan ID:%d tidak ditemukan", methodID)}
+			helpers.ResponseJSON(ctx, http.StatusNotFound, response)
+			return
+		}
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	algorithm, ok := ranking.Get(getMethod.Algorithm)
+	if !ok {
+		response := map[string]string{"error": fmt.Sprintf("metode %s belum terhubung dengan algoritma perhitungan", getMethod.Name)}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	_, matrix, err := ranking.LoadMatrix(service.criteriaRepository, service.criteriaScoreRepository, getPeriod.ID)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	intervals, err := criteriaIntervals(matrix, request.Intervals, spread)
+	if err != nil {
+		response := map[string]string{"error": err.Error()}
+		helpers.ResponseJSON(ctx, http.StatusBadRequest, response)
+		return
+	}
+
+	sampler := SimplexSampler(len(matrix.Criteria))
+	if request.Sampling == SamplingInterval {
+		minWeights := make([]float64, len(intervals))
+		maxWeights := make([]float64, len(intervals))
+		for j, interval := range intervals {
+			minWeights[j], maxWeights[j] = interval.Min, interval.Max
+		}
+		sampler = IntervalSampler(minWeights, maxWeights)
+	} else {
+		// simplex mencakup seluruh kemungkinan bobot
+		for j := range intervals {
+			intervals[j].Min, intervals[j].Max = 0, 1
+		}
+	}
+
+	products, err := service.productRepository.GetAllProductRepository()
+	if err != nil {
+		response := map[string]string{"error": "gagal mengambil data produk"}
+		helpers.ResponseJSON(ctx, http.StatusInternalServerError, response)
+		return
+	}
+
+	productNames := make(map[int]string, len(products))
+	for _, getProduct := range products {
+		productNames[getProduct.ID] = getProduct.Name
+	}
+
+	result := Analyze(algorithm, matrix, sampler, request.Samples, request.TopRanks, seed)
+	for i := range result {
+		result[i].ProductName = productNames[result[i].ProductID]
+	}
+	sort.SliceStable(result, func(a, b int) bool {
+		return result[a].BaseRank < result[b].BaseRank
+	})
+
+	response := ResponseSMAA{
+		MethodID:  methodID,
+		PeriodID:  getPeriod.ID,
+		Algorithm: algorithm.Name(),
+		Sampling:  request.Sampling,
+		Samples:   request.Samples,
+		TopRanks:  min(request.TopRanks, len(matrix.ProductIDs)),
+		Seed:      seed,
+		Criteria:  intervals,
+		Products:  result,
+	}
+	helpers.ResponseJSON(ctx, http.StatusOK, response)
+}
+
+// criteriaIntervals menyusun batas bobot setiap kriteria sesuai urutan kolom matriks
+func criteriaIntervals(matrix ranking.Matrix, requested []RequestInterval, spread float64) ([]ResponseCriteriaInterval, error) {
+	columns := make(map[int]int, len(matrix.Criteria))
+	intervals := make([]ResponseCriteriaInterval, len(matrix.Criteria))
+	for j, criterion := range matrix.Criteria {
+		columns[criterion.ID] = j
+		minWeight, maxWeight := defaultInterval(criterion.Weight, spread)
+		intervals[j] = ResponseCriteriaInterval{
+			CriteriaID:   criterion.ID,
+			CriteriaName: criterion.Name,
+			Weight:       criterion.Weight,
+			Min:          minWeight,
+			Max:          maxWeight,
+		}
+	}
+
+	for _, interval := range requested {
+		j, exists := columns[interval.CriteriaID]
+		if !exists {
+			return nil, fmt.Errorf("Kriteria dengan ID:%d tidak ditemukan", interval.CriteriaID)
+		}
+
+		if interval.Min < 0 || interval.Max < interval.Min {
+			return nil, fmt.Errorf("interval kriteria %s tidak valid, min harus >= 0 dan max >= min", intervals[j].CriteriaName)
+		}
+		intervals[j].Min, intervals[j].Max = interval.Min, interval.Max
+	}
+	return intervals, nil
+}